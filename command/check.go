@@ -32,7 +32,8 @@ FLAGS
 `
 
 type CheckCommand struct {
-	flagParser string
+	flagParser  string
+	flagExclude stringSliceFlag
 }
 
 func (c *CheckCommand) Hidden() {}
@@ -49,6 +50,8 @@ func (c *CheckCommand) Flags() *flag.FlagSet {
 	}
 
 	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
 
 	return f
 }
@@ -66,7 +69,13 @@ func (c *CheckCommand) Run(ctx context.Context, originalArgs []string) error {
 		return err
 	}
 
-	loadResult, err := loadYAMLFiles(os.DirFS("."), args)
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
+	}
+
+	loadResult, err := loadYAMLFiles(fsys, files)
 	if err != nil {
 		return err
 	}
@@ -7,28 +7,36 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	// Using banydonk/yaml instead of the default yaml pkg because the default
 	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
 	"github.com/braydonk/yaml"
 	"github.com/sethvargo/ratchet/internal/atomic"
+	"github.com/sethvargo/ratchet/internal/diff"
+	"github.com/sethvargo/ratchet/internal/dockerfile"
 	"github.com/sethvargo/ratchet/internal/version"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 // Commands is the main list of all commands.
 var Commands = map[string]Command{
 	"check":   &CheckCommand{},
 	"lint":    &LintCommand{},
+	"lock":    &LockCommand{},
 	"pin":     &PinCommand{},
 	"unpin":   &UnpinCommand{},
 	"update":  &UpdateCommand{},
 	"upgrade": &UpgradeCommand{},
+	"verify":  &VerifyCommand{},
 }
 
 // Command is the interface for a subcommand.
@@ -82,6 +90,18 @@ func parseFlags(f *flag.FlagSet, args []string) ([]string, error) {
 	return finalArgs, merr
 }
 
+// stringSliceFlag is a flag that can be given multiple times.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
 // extractCommandAndArgs is a helper that pulls the subcommand and arguments.
 func extractCommandAndArgs(args []string) (string, []string) {
 	switch len(args) {
@@ -94,15 +114,45 @@ func extractCommandAndArgs(args []string) (string, []string) {
 	}
 }
 
-// marshalYAML encodes the yaml node into the given writer.
-func marshalYAML(m *yaml.Node) (string, error) {
+// newResolver creates the default resolver. Unless noCache is true, the
+// resolver is wrapped in an on-disk cache stored in cacheDir (or the default
+// cache directory if empty).
+func newResolver(ctx context.Context, noCache bool, cacheDir string, cacheTTL time.Duration) (resolver.Resolver, error) {
+	res, err := resolver.NewDefaultResolver(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resolver: %w", err)
+	}
+
+	if noCache {
+		return res, nil
+	}
+
+	if cacheDir == "" {
+		cacheDir, err = resolver.DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cache, err := resolver.NewCache(res, cacheDir, cacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resolver cache: %w", err)
+	}
+	return cache, nil
+}
+
+// marshalYAML encodes the yaml documents into a string. Multiple documents are
+// separated by "---".
+func marshalYAML(docs ...*yaml.Node) (string, error) {
 	var b bytes.Buffer
 
 	enc := yaml.NewEncoder(&b)
 	enc.SetIndent(2)
 	enc.SetAssumeBlockAsLiteral(true)
-	if err := enc.Encode(m); err != nil {
-		return "", fmt.Errorf("failed to encode yaml: %w", err)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return "", fmt.Errorf("failed to encode yaml: %w", err)
+		}
 	}
 	if err := enc.Close(); err != nil {
 		return "", fmt.Errorf("failed to finalize yaml: %w", err)
@@ -111,13 +161,54 @@ func marshalYAML(m *yaml.Node) (string, error) {
 }
 
 type loadResult struct {
-	node     *yaml.Node
+	// node is the document passed to parsers. For files with multiple
+	// documents, it is a synthetic document that holds the contents of every
+	// document, since parsers already iterate over all document contents.
+	node *yaml.Node
+
+	// documents are the individual documents in the file, in order.
+	documents []*yaml.Node
+
+	// scalars is the state of every scalar node when the file was loaded, used
+	// to patch only the changed values into the original contents.
+	scalars map[*yaml.Node]*scalarSnapshot
+
 	contents string
 	newlines []int
+
+	// dockerfile is true if the file is a Dockerfile, which is decoded into a
+	// synthetic document and can only be patched, never re-encoded.
+	dockerfile bool
+}
+
+// render renders the file. By default, only changed values and comments are
+// patched into the original contents. If reformat is true, or if the changes
+// cannot be patched, the documents are re-encoded entirely.
+func (r *loadResult) render(reformat bool) (string, error) {
+	if !reformat && !r.dockerfile {
+		if s, ok := r.patchYAML(); ok {
+			return s, nil
+		}
+	}
+	return r.marshalYAML()
 }
 
 func (r *loadResult) marshalYAML() (string, error) {
-	contents, err := marshalYAML(r.node)
+	// Dockerfiles cannot be re-encoded, so they are always patched.
+	if r.dockerfile {
+		s, ok := r.patchDockerfile()
+		if !ok {
+			return "", fmt.Errorf("failed to patch dockerfile")
+		}
+		return s, nil
+	}
+
+	// Files without any documents (e.g. empty files) have nothing to render.
+	if len(r.documents) == 0 {
+		return r.contents, nil
+	}
+
+	contents, err := marshalYAML(r.documents...)
 	if err != nil {
 		return "", err
 	}
@@ -161,37 +252,90 @@ func loadYAMLFiles(fsys fs.FS, paths []string) (loadResults, error) {
 			return nil, fmt.Errorf("failed to read file %s: %w", pth, err)
 		}
 
-		var node yaml.Node
-		dec := yaml.NewDecoder(bytes.NewReader(contents))
-		dec.SetScanBlockScalarAsLiteral(true)
-		if err := dec.Decode(&node); err != nil {
+		if _, ok := r[pth]; ok {
+			return nil, fmt.Errorf("internal error: entry already exists for %q: %v", pth, r)
+		}
+
+		if dockerfile.IsDockerfile(pth) {
+			document, err := dockerfile.Decode(contents)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse dockerfile for %s: %w", pth, err)
+			}
+
+			r[pth] = &loadResult{
+				node:       document,
+				documents:  []*yaml.Node{document},
+				scalars:    snapshotScalars([]*yaml.Node{document}),
+				contents:   string(contents),
+				dockerfile: true,
+			}
+			continue
+		}
+
+		documents, err := decodeYAMLDocuments(contents)
+		if err != nil {
 			return nil, fmt.Errorf("failed to parse yaml for %s: %w", pth, err)
 		}
 
 		// Remarshal the content before any modification so we can compute the
 		// places where a newline should be inserted post-rendering.
-		remarshaled, err := marshalYAML(&node)
+		remarshaled, err := marshalYAML(documents...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to remarshal yaml for %s: %w", pth, err)
 		}
 
 		newlines := computeNewlineTargets(string(contents), remarshaled)
 
-		if _, ok := r[pth]; ok {
-			return nil, fmt.Errorf("internal error: entry already exists for %q: %v", pth, r)
-		}
-
 		r[pth] = &loadResult{
-			node:     &node,
-			contents: string(contents),
-			newlines: newlines,
+			node:      combineYAMLDocuments(documents),
+			documents: documents,
+			scalars:   snapshotScalars(documents),
+			contents:  string(contents),
+			newlines:  newlines,
 		}
 	}
 
 	return r, nil
 }
 
-func (r loadResults) writeYAMLFiles(outPath string) error {
+// decodeYAMLDocuments decodes all documents in the contents. Documents are
+// separated by "---".
+func decodeYAMLDocuments(contents []byte) ([]*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(contents))
+	dec.SetScanBlockScalarAsLiteral(true)
+
+	var documents []*yaml.Node
+	for {
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		documents = append(documents, &node)
+	}
+	return documents, nil
+}
+
+// combineYAMLDocuments returns a single document node that contains the
+// contents of all the given documents. The returned node shares the child
+// nodes, so changes made by parsers are reflected in the original documents.
+func combineYAMLDocuments(documents []*yaml.Node) *yaml.Node {
+	if len(documents) == 1 {
+		return documents[0]
+	}
+
+	combined := &yaml.Node{
+		Kind: yaml.DocumentNode,
+	}
+	for _, doc := range documents {
+		combined.Content = append(combined.Content, doc.Content...)
+	}
+	return combined
+}
+
+func (r loadResults) writeYAMLFiles(outPath string, reformat bool) error {
 	var merr error
 
 	for pth, f := range r {
@@ -203,7 +347,7 @@ func (r loadResults) writeYAMLFiles(outPath string) error {
 			outFile = pth
 		}
 
-		final, err := f.marshalYAML()
+		final, err := f.render(reformat)
 		if err != nil {
 			merr = errors.Join(merr, fmt.Errorf("failed to marshal yaml for %s: %w", pth, err))
 			continue
@@ -218,6 +362,54 @@ func (r loadResults) writeYAMLFiles(outPath string) error {
 	return merr
 }
 
+// diffYAMLFiles writes a unified diff to w for each file that would change,
+// without writing any files. It returns the number of files that would change.
+func (r loadResults) diffYAMLFiles(w io.Writer, reformat bool) (int, error) {
+	var merr error
+	changed := 0
+
+	for _, pth := range slices.Sorted(maps.Keys(r)) {
+		f := r[pth]
+
+		final, err := f.render(reformat)
+		if err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to marshal yaml for %s: %w", pth, err))
+			continue
+		}
+
+		if d := diff.Unified("a/"+pth, "b/"+pth, f.contents, final); d != "" {
+			changed++
+			if _, err := io.WriteString(w, d); err != nil {
+				merr = errors.Join(merr, fmt.Errorf("failed to write diff for %s: %w", pth, err))
+			}
+		}
+	}
+
+	return changed, merr
+}
+
+// saveYAMLFiles writes the files to outPath. In dry-run or check mode, it
+// prints a diff of the changes instead, and check mode returns an error if any
+// file would change.
+func (r loadResults) saveYAMLFiles(outPath string, reformat, dryRun, check bool) error {
+	if !dryRun && !check {
+		if err := r.writeYAMLFiles(outPath, reformat); err != nil {
+			return fmt.Errorf("failed to save files: %w", err)
+		}
+		return nil
+	}
+
+	changed, err := r.diffYAMLFiles(os.Stdout, reformat)
+	if err != nil {
+		return fmt.Errorf("failed to diff files: %w", err)
+	}
+
+	if check && changed > 0 {
+		return fmt.Errorf("%d file(s) would change", changed)
+	}
+	return nil
+}
+
 func computeNewlineTargets(before, after string) []int {
 	before = strings.TrimPrefix(before, "---\n")
 
@@ -5,17 +5,31 @@ package command
 const topLevelHelp = `Usage: ratchet COMMAND
 
   lint       Lint and report unpinned versions
+  lock       Record resolved versions in a lockfile
   pin        Resolve and pin all versions
   unpin      Revert pinned versions to their unpinned values
   update     Update all pinned versions to the latest value
   upgrade    Upgrade all pinned versions to the latest version
+  verify     Verify pinned versions match their ratchet comments
 
 Available parsers:
 
   actions
+  argo
+  auto
+  azurepipelines
+  bitbucket
+  buildkite
   circleci
   cloudbuild
+  compose
+  dockerfile
   drone
+  gitea
   gitlabci
+  helm
+  kubernetes
+  precommit
   tekton
+  woodpecker
 `
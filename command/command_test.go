@@ -1,12 +1,16 @@
 package command
 
 import (
+	"context"
 	"io/fs"
 	"os"
+	"strings"
 	"testing"
 	"testing/fstest"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sethvargo/ratchet/parser"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 func Test_loadYAMLFiles(t *testing.T) {
@@ -16,18 +20,28 @@ func Test_loadYAMLFiles(t *testing.T) {
 
 	cases := map[string]string{
 		"a.yml":                   "a.golden.yml",
+		"argo.yml":                "",
 		"b.yml":                   "b.golden.yml",
 		"c.yml":                   "",
 		"circleci.yml":            "",
 		"cloudbuild.yml":          "",
+		"compose.yml":             "",
 		"docker.yml":              "",
 		"drone.yml":               "",
 		"github-crazy-indent.yml": "github.yml",
 		"github-issue-80.yml":     "",
+		"azurepipelines.yml":      "",
+		"bitbucket.yml":           "",
+		"buildkite.yml":           "",
 		"github.yml":              "",
+		"helm.yml":                "",
+		"kubernetes.yml":          "",
 		"gitlabci.yml":            "",
+		"multi-document.yml":      "",
 		"no-trailing-newline.yml": "no-trailing-newline.golden.yml",
+		"precommit.yml":           "",
 		"tekton.yml":              "",
+		"woodpecker.yml":          "",
 	}
 
 	for input, expected := range cases {
@@ -59,6 +73,151 @@ func Test_loadYAMLFiles(t *testing.T) {
 	}
 }
 
+func Test_loadYAMLFiles_pinUnpin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	fsys := os.DirFS("../testdata")
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"container://golang:1.12": {
+			Resolved: "golang@sha256:12d3995156cb0dcdbb9d3edb5827e4e8e1bf5bf92436bfd12d696ec997001a9a",
+		},
+		"container://ubuntu:20.04": {
+			Resolved: "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
+		},
+		"container://gcr.io/google.com/cloudsdktool/google-cloud-cli:slim": {
+			Resolved: "gcr.io/google.com/cloudsdktool/google-cloud-cli@sha256:a6a7bd2e6a8f9c3e8d1d7fbd0a7a4c8f9e0e4d3fd9f3ab8c39a2c4a4c6a2b5e1",
+		},
+		"container://golang:1.24": {
+			Resolved: "golang@sha256:4f3f7cf8b9d8a3b1c0a2a3e0ab3e2c5f8b4d7a5e1f2c3b4a5d6e7f8091a2b3c4",
+		},
+		"container://docker.io/ubuntu:24.04": {
+			Resolved: "index.docker.io/library/ubuntu@sha256:6015f66923d7afbc53558d7ccffd325d43b4e249f41a6e93eef074c9505d2233",
+		},
+		"container://ubuntu:24.04": {
+			Resolved: "ubuntu@sha256:6015f66923d7afbc53558d7ccffd325d43b4e249f41a6e93eef074c9505d2233",
+		},
+		"container://atlassian/default-image:3": {
+			Resolved: "atlassian/default-image@sha256:9a3b1e3c7f0c58b7d7e1f1d5b1c5a4ab8c8d6e6f2f0b3a8c9d7e1f2a3b4c5d6e",
+		},
+		"container://node:18": {
+			Resolved: "node@sha256:a6385a6bb2fdcb7c48fc871e35e32af8daaa82c518900be49b76d10c005864c2",
+		},
+		"container://postgres:16": {
+			Resolved: "postgres@sha256:4aea012537edfad80f98d870a36e6b90b4c09b27be7f4b4759d72db863baeebb",
+		},
+		"container://alpine:3.20": {
+			Resolved: "alpine@sha256:beefdbd8a1da6d2915566fde36db9db0b524eb737fc57cd1367effd16dc0d06d",
+		},
+		"actions://actions/checkout@v4": {
+			Resolved: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		"actions://buildkite-plugins/docker-buildkite-plugin@v5.9.0": {
+			Resolved: "buildkite-plugins/docker-buildkite-plugin@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		"actions://buildkite-plugins/docker-compose-buildkite-plugin@v4.16.0": {
+			Resolved: "buildkite-plugins/docker-compose-buildkite-plugin@2541b1294d2704b0964813337f33b291d3f8596b",
+		},
+		"actions://my-org/cache-buildkite-plugin@v1.0.0": {
+			Resolved: "my-org/cache-buildkite-plugin@a12a3943b4bdde767164f792f33f40b04645d846",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		input    string
+		expected string
+		parser   parser.Parser
+	}{
+		{
+			input:    "azurepipelines.yml",
+			expected: "azurepipelines-pinned.golden.yml",
+			parser:   new(parser.AzurePipelines),
+		},
+		{
+			input:    "bitbucket.yml",
+			expected: "bitbucket-pinned.golden.yml",
+			parser:   new(parser.Bitbucket),
+		},
+		{
+			input:    "buildkite.yml",
+			expected: "buildkite-pinned.golden.yml",
+			parser:   new(parser.Buildkite),
+		},
+		{
+			input:    "dockerfile.Dockerfile",
+			expected: "dockerfile-pinned.golden.Dockerfile",
+			parser:   new(parser.Dockerfile),
+		},
+		{
+			input:    "helm.yml",
+			expected: "helm-pinned.golden.yml",
+			parser:   new(parser.Helm),
+		},
+		{
+			input:    "kubernetes.yml",
+			expected: "kubernetes-pinned.golden.yml",
+			parser:   new(parser.Kubernetes),
+		},
+		{
+			input:    "multi-document.yml",
+			expected: "multi-document-pinned.golden.yml",
+			parser:   new(parser.Tekton),
+		},
+	}
+
+	for _, tc := range cases {
+		input, expected := tc.input, tc.expected
+
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			original, err := fsys.(fs.ReadFileFS).ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := fsys.(fs.ReadFileFS).ReadFile(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := loadYAMLFiles(fsys, []string{input})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := parser.Pin(ctx, res, tc.parser, files.nodes(), 1); err != nil {
+				t.Fatal(err)
+			}
+
+			pinned, err := files[input].marshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(pinned, string(want)); diff != "" {
+				t.Errorf("unexpected pin diff (+got, -want):\n%s", diff)
+			}
+
+			if err := parser.Unpin(ctx, tc.parser, files.nodes()); err != nil {
+				t.Fatal(err)
+			}
+
+			unpinned, err := files[input].marshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(unpinned, string(original)); diff != "" {
+				t.Errorf("unexpected unpin diff (+got, -want):\n%s", diff)
+			}
+		})
+	}
+}
+
 func Test_computeNewlineTargets_simple(t *testing.T) {
 	t.Parallel()
 
@@ -192,6 +351,17 @@ test-code-job1:
 `,
 			want: []int{6},
 		},
+		{
+			name: "multiple_documents",
+			yaml: `foo: bar
+
+---
+bar: baz
+---
+baz: qux
+`,
+			want: []int{1},
+		},
 	}
 
 	for _, tc := range cases {
@@ -224,3 +394,68 @@ test-code-job1:
 		})
 	}
 }
+
+func Test_loadResults_diffYAMLFiles(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"pinned.yml": &fstest.MapFile{
+			Data: []byte("jobs:\n  my_job:\n    steps:\n      - uses: 'actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683'\n"),
+		},
+		"unpinned.yml": &fstest.MapFile{
+			Data: []byte("jobs:\n  my_job:\n    steps:\n      - uses: 'actions/checkout@v4'\n"),
+		},
+	}
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://actions/checkout@v4": {
+			Resolved: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		"actions://buildkite-plugins/docker-buildkite-plugin@v5.9.0": {
+			Resolved: "buildkite-plugins/docker-buildkite-plugin@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		"actions://buildkite-plugins/docker-compose-buildkite-plugin@v4.16.0": {
+			Resolved: "buildkite-plugins/docker-compose-buildkite-plugin@2541b1294d2704b0964813337f33b291d3f8596b",
+		},
+		"actions://my-org/cache-buildkite-plugin@v1.0.0": {
+			Resolved: "my-org/cache-buildkite-plugin@a12a3943b4bdde767164f792f33f40b04645d846",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := loadYAMLFiles(fsys, []string{"pinned.yml", "unpinned.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parser.Pin(ctx, res, new(parser.Actions), files.nodes(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	changed, err := files.diffYAMLFiles(&b, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := changed, 1; got != want {
+		t.Errorf("expected %d changed files to be %d", got, want)
+	}
+
+	want := `--- a/unpinned.yml
++++ b/unpinned.yml
+@@ -1,4 +1,4 @@
+ jobs:
+   my_job:
+     steps:
+-      - uses: 'actions/checkout@v4'
++      - uses: 'actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683' # ratchet:actions/checkout@v4
+`
+	if diff := cmp.Diff(b.String(), want); diff != "" {
+		t.Errorf("unexpected diff (+got, -want):\n%s", diff)
+	}
+}
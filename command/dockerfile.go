@@ -0,0 +1,98 @@
+package command
+
+import (
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/internal/dockerfile"
+)
+
+// patchDockerfile splices changed values into the original Dockerfile
+// contents. Dockerfiles do not support trailing comments, so line comments are
+// written on their own line directly above the instruction. It returns false if
+// any change cannot be safely patched.
+func (r *loadResult) patchDockerfile() (string, bool) {
+	newline := "\n"
+	if strings.Contains(r.contents, "\r\n") {
+		newline = "\r\n"
+	}
+
+	var edits []*textEdit
+	ok := true
+
+	for _, instruction := range dockerfile.Instructions(r.node) {
+		walkScalars(instruction.Content, func(node *yaml.Node) {
+			if !ok {
+				return
+			}
+
+			snapshot, found := r.scalars[node]
+			if !found {
+				ok = false
+				return
+			}
+
+			if node.Value != snapshot.value {
+				start, lineEnd, found := findColumn(r.contents, node.Line, node.Column)
+				if !found || snapshot.value == "" ||
+					!strings.HasPrefix(r.contents[start:lineEnd], snapshot.value) ||
+					node.Value == "" || strings.ContainsAny(node.Value, " \t\r\n") {
+					ok = false
+					return
+				}
+				edits = append(edits, &textEdit{start: start, end: start + len(snapshot.value), text: node.Value})
+			}
+
+			if node.LineComment != snapshot.comment {
+				edit, found := patchDockerfileComment(r.contents, instruction.Line, node, snapshot, newline)
+				if !found {
+					ok = false
+					return
+				}
+				edits = append(edits, edit)
+			}
+		})
+	}
+	if !ok {
+		return "", false
+	}
+
+	return applyEdits(r.contents, edits)
+}
+
+// patchDockerfileComment computes the edit to change the comment above the
+// instruction at the given line from its snapshot state to its current state.
+func patchDockerfileComment(contents string, instructionLine int, node *yaml.Node, snapshot *scalarSnapshot, newline string) (*textEdit, bool) {
+	instructionStart, instructionEnd, found := findLine(contents, instructionLine)
+	if !found {
+		return nil, false
+	}
+	line := contents[instructionStart:instructionEnd]
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+	comment := node.LineComment
+	if comment != "" && !strings.HasPrefix(comment, "#") {
+		comment = "# " + comment
+	}
+
+	// There was no comment, so insert one above the instruction.
+	if snapshot.comment == "" {
+		return &textEdit{start: instructionStart, end: instructionStart, text: indent + comment + newline}, true
+	}
+
+	// Otherwise, the comment is on the line directly above the instruction.
+	commentStart, commentEnd, found := findLine(contents, instructionLine-1)
+	if !found || strings.TrimSpace(contents[commentStart:commentEnd]) != snapshot.comment {
+		return nil, false
+	}
+
+	if comment == "" {
+		return &textEdit{start: commentStart, end: instructionStart, text: ""}, true
+	}
+
+	commentLine := contents[commentStart:commentEnd]
+	commentStart += len(commentLine) - len(strings.TrimLeft(commentLine, " \t"))
+	return &textEdit{start: commentStart, end: commentEnd, text: comment}, true
+}
@@ -0,0 +1,269 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sethvargo/ratchet/internal/dockerfile"
+)
+
+// expandPaths expands the given arguments into a sorted list of files:
+//
+//   - Files are returned as-is.
+//   - Directories are walked recursively for ".yml" and ".yaml" files, and
+//     Dockerfiles.
+//   - Glob patterns, including "**" to match any number of directories, are
+//     matched against all files in the pattern's static parent directory.
+//
+// Files and directories matched by a .gitignore are skipped while walking, but
+// files given explicitly are always included. Any path matching one of the
+// exclude patterns is removed, regardless of how it was found.
+func expandPaths(fsys fs.FS, args, excludes []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(args))
+	result := make([]string, 0, len(args))
+
+	add := func(pth string) {
+		if isExcluded(excludes, pth) {
+			return
+		}
+		if _, ok := seen[pth]; ok {
+			return
+		}
+		seen[pth] = struct{}{}
+		result = append(result, pth)
+	}
+
+	for _, arg := range args {
+		arg = filepath.ToSlash(filepath.Clean(arg))
+
+		if hasGlobMeta(arg) {
+			root := globRoot(arg)
+			if err := walkFiles(fsys, root, func(pth string) {
+				if matchGlob(arg, pth) {
+					add(pth)
+				}
+			}); err != nil {
+				return nil, fmt.Errorf("failed to expand %s: %w", arg, err)
+			}
+			continue
+		}
+
+		info, err := fs.Stat(fsys, arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", arg, err)
+		}
+
+		if !info.IsDir() {
+			add(arg)
+			continue
+		}
+
+		if err := walkFiles(fsys, arg, func(pth string) {
+			if ext := path.Ext(pth); ext == ".yml" || ext == ".yaml" || dockerfile.IsDockerfile(pth) {
+				add(pth)
+			}
+		}); err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", arg, err)
+		}
+	}
+
+	slices.Sort(result)
+	return result, nil
+}
+
+// walkFiles recursively walks root, calling fn for each file that is not
+// ignored by a .gitignore file. The .git directory is always skipped.
+func walkFiles(fsys fs.FS, root string, fn func(pth string)) error {
+	var ignores gitignore
+
+	// Load any .gitignore files from the parent directories of the root, since
+	// they apply to the files inside of it.
+	if root != "." {
+		dir := "."
+		for _, part := range strings.Split(path.Dir(root), "/") {
+			if err := ignores.load(fsys, dir); err != nil {
+				return err
+			}
+			dir = path.Join(dir, part)
+		}
+		if dir != "." {
+			if err := ignores.load(fsys, dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	return fs.WalkDir(fsys, root, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			if pth != root && ignores.match(pth, true) {
+				return fs.SkipDir
+			}
+			return ignores.load(fsys, pth)
+		}
+
+		if !ignores.match(pth, false) {
+			fn(pth)
+		}
+		return nil
+	})
+}
+
+// gitignore is a list of rules from .gitignore files.
+type gitignore []*gitignoreRule
+
+// gitignoreRule is a single pattern from a .gitignore file.
+type gitignoreRule struct {
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// load parses the .gitignore file in dir, if one exists, and appends its rules.
+func (g *gitignore) load(fsys fs.FS, dir string) error {
+	b, err := fs.ReadFile(fsys, path.Join(dir, ".gitignore"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := &gitignoreRule{base: dir}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		rule.pattern = line
+
+		*g = append(*g, rule)
+	}
+	return scanner.Err()
+}
+
+// match returns true if the path is ignored. Later rules take precedence over
+// earlier rules, which allows negation.
+func (g gitignore) match(pth string, isDir bool) bool {
+	ignored := false
+	for _, rule := range g {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		rel := pth
+		if rule.base != "." {
+			if !strings.HasPrefix(pth, rule.base+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(pth, rule.base+"/")
+		}
+
+		var matched bool
+		if rule.anchored {
+			matched = matchGlob(rule.pattern, rel)
+		} else {
+			matched = matchGlob(rule.pattern, path.Base(rel))
+		}
+
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// isExcluded returns true if the path, or any of its parent directories,
+// matches one of the exclude patterns.
+func isExcluded(excludes []string, pth string) bool {
+	for _, exclude := range excludes {
+		exclude = filepath.ToSlash(filepath.Clean(exclude))
+
+		for dir := pth; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if matchGlob(exclude, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasGlobMeta returns true if the path contains glob characters.
+func hasGlobMeta(pth string) bool {
+	return strings.ContainsAny(pth, "*?[")
+}
+
+// globRoot returns the longest leading directory of the pattern that does not
+// contain any glob characters.
+func globRoot(pattern string) string {
+	parts := strings.Split(pattern, "/")
+	for i, part := range parts {
+		if hasGlobMeta(part) {
+			if i == 0 {
+				return "."
+			}
+			return path.Join(parts[:i]...)
+		}
+	}
+	return pattern
+}
+
+// matchGlob returns true if the slash-separated name matches the pattern. In
+// addition to the syntax supported by [path.Match], a "**" segment matches
+// zero or more directories.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
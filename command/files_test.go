@@ -0,0 +1,155 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_expandPaths(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		".gitignore":                       &fstest.MapFile{Data: []byte("build/\n*.tmp.yml\n")},
+		".git/config.yml":                  &fstest.MapFile{},
+		".github/workflows/test.yml":       &fstest.MapFile{},
+		".github/workflows/release.yaml":   &fstest.MapFile{},
+		".github/workflows/README.md":      &fstest.MapFile{},
+		".github/dependabot.yml":           &fstest.MapFile{},
+		".gitlab-ci.yml":                   &fstest.MapFile{},
+		"Dockerfile":                       &fstest.MapFile{},
+		"Dockerfile.dockerignore":          &fstest.MapFile{},
+		"build/output.yml":                 &fstest.MapFile{},
+		"deploy/.gitignore":                &fstest.MapFile{Data: []byte("*.yml\n!keep.yml\n")},
+		"deploy/keep.yml":                  &fstest.MapFile{},
+		"deploy/drop.yml":                  &fstest.MapFile{},
+		"scratch.tmp.yml":                  &fstest.MapFile{},
+		"vendor/github.com/foo/action.yml": &fstest.MapFile{},
+	}
+
+	cases := []struct {
+		name     string
+		args     []string
+		excludes []string
+		exp      []string
+		err      string
+	}{
+		{
+			name: "file",
+			args: []string{".gitlab-ci.yml"},
+			exp:  []string{".gitlab-ci.yml"},
+		},
+		{
+			name: "ignored_file_explicit",
+			args: []string{"scratch.tmp.yml"},
+			exp:  []string{"scratch.tmp.yml"},
+		},
+		{
+			name: "directory",
+			args: []string{"."},
+			exp: []string{
+				".github/dependabot.yml",
+				".github/workflows/release.yaml",
+				".github/workflows/test.yml",
+				".gitlab-ci.yml",
+				"Dockerfile",
+				"deploy/keep.yml",
+				"vendor/github.com/foo/action.yml",
+			},
+		},
+		{
+			name: "directory_nested_with_parent_gitignore",
+			args: []string{"deploy"},
+			exp:  []string{"deploy/keep.yml"},
+		},
+		{
+			name: "glob",
+			args: []string{".github/workflows/*.yml"},
+			exp:  []string{".github/workflows/test.yml"},
+		},
+		{
+			name: "doublestar",
+			args: []string{".github/**/*.yml"},
+			exp: []string{
+				".github/dependabot.yml",
+				".github/workflows/test.yml",
+			},
+		},
+		{
+			name:     "exclude",
+			args:     []string{"."},
+			excludes: []string{"vendor", ".github/**/*.yaml"},
+			exp: []string{
+				".github/dependabot.yml",
+				".github/workflows/test.yml",
+				".gitlab-ci.yml",
+				"Dockerfile",
+				"deploy/keep.yml",
+			},
+		},
+		{
+			name: "duplicates",
+			args: []string{".gitlab-ci.yml", "*.yml"},
+			exp:  []string{".gitlab-ci.yml"},
+		},
+		{
+			name: "missing",
+			args: []string{"nope.yml"},
+			err:  "failed to read file nope.yml",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := expandPaths(fsys, tc.args, tc.excludes)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+					t.Errorf("expected %q to contain %q", got, want)
+				}
+				return
+			} else if tc.err != "" {
+				t.Fatalf("expected error, got %q", got)
+			}
+
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("unexpected paths (+got, -want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_matchGlob(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		name    string
+		exp     bool
+	}{
+		{"*.yml", "a.yml", true},
+		{"*.yml", "dir/a.yml", false},
+		{"**/*.yml", "a.yml", true},
+		{"**/*.yml", "dir/sub/a.yml", true},
+		{"dir/**", "dir/sub/a.yml", true},
+		{"dir/**/a.yml", "dir/a.yml", true},
+		{"dir/**/a.yml", "other/a.yml", false},
+		{".github/**/*.yml", ".github/workflows/test.yml", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern+"_"+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := matchGlob(tc.pattern, tc.name), tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
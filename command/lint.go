@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/sethvargo/ratchet/formatter"
+	"github.com/sethvargo/ratchet/internal/concurrency"
 	"github.com/sethvargo/ratchet/parser"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 const lintCommandDesc = `Lint and report unpinned versions`
@@ -20,20 +22,33 @@ Usage: ratchet lint [FILE...]
 The "lint" command reports any unpinned versions, ignoring any versions with
 the "ratchet:exclude" comment.
 
-If any versions are unpinned, it returns a non-zero exit code. This command does
-not communicate with upstream APIs or services.
+If any versions are unpinned, it returns a non-zero exit code. By default, this
+command does not communicate with upstream APIs or services.
+
+With "-impostors", it also verifies that every pinned GitHub Actions commit is
+reachable from the default branch of the referenced repository, or from a
+branch or tag that matches the version in its ratchet comment. GitHub serves
+commits from forks under the parent repository's name, so a pinned commit may
+not belong to the upstream repository at all. This mode calls the GitHub API
+about three times, plus once per matching branch or tag, for every pinned
+commit.
 
 EXAMPLES
 
   ratchet lint ./path/to/file.yaml
 
+  ratchet lint -impostors ./path/to/file.yaml
+
 FLAGS
 
 `
 
 type LintCommand struct {
-	flagFormat string
-	flagParser string
+	flagFormat      string
+	flagParser      string
+	flagExclude     stringSliceFlag
+	flagImpostors   bool
+	flagConcurrency int64
 }
 
 func (c *LintCommand) Desc() string {
@@ -54,6 +69,13 @@ func (c *LintCommand) Flags() *flag.FlagSet {
 
 	f.StringVar(&c.flagFormat, "format", format, "linter output format")
 	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
+	f.BoolVar(&c.flagImpostors, "impostors", false,
+		"verify pinned commits are reachable from the upstream repository "+
+			"(about 3 GitHub API calls per pinned commit, plus 1 per matching branch or tag)")
+	f.Int64Var(&c.flagConcurrency, "concurrency", concurrency.DefaultConcurrency(1),
+		"maximum number of concurrent verifications")
 
 	return f
 }
@@ -69,7 +91,13 @@ func (c *LintCommand) Run(ctx context.Context, originalArgs []string) error {
 		return err
 	}
 
-	loadResult, err := loadYAMLFiles(os.DirFS("."), args)
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
+	}
+
+	loadResult, err := loadYAMLFiles(fsys, files)
 	if err != nil {
 		return err
 	}
@@ -79,6 +107,24 @@ func (c *LintCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to run linter: %w", err)
 	}
 
+	if c.flagImpostors {
+		res, err := resolver.NewDefaultResolver(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create resolver: %w", err)
+		}
+
+		verifier, ok := res.(resolver.CommitVerifier)
+		if !ok {
+			return fmt.Errorf("resolver %T does not support commit verification", res)
+		}
+
+		impostors, err := parser.Impostors(ctx, verifier, par, loadResult.nodes(), c.flagConcurrency)
+		if err != nil {
+			return fmt.Errorf("failed to verify commits: %w", err)
+		}
+		violations = append(violations, impostors...)
+	}
+
 	fmter, err := formatter.For(ctx, c.flagFormat)
 	if err != nil {
 		return err
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sethvargo/ratchet/internal/concurrency"
+	"github.com/sethvargo/ratchet/parser"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+const lockCommandDesc = `Record resolved versions in a lockfile`
+
+const lockCommandHelp = `
+Usage: ratchet lock [FILE...]
+
+The "lock" command resolves all versions in the given input files and records
+the results in a lockfile, without modifying the input files:
+
+    actions://actions/checkout@v4 -> actions/checkout@11bd71901bbe5b1630ce...
+
+Pinned versions are recorded under their original unpinned value from the
+Ratchet comment. The lockfile can be reviewed separately and used to pin files
+without contacting upstream APIs via "ratchet pin -offline".
+
+The lockfile is rewritten on each run, so it only contains versions from the
+given input files. Versions are always resolved from upstream APIs and never
+from the resolution cache, so the recorded resolution times are accurate.
+
+EXAMPLES
+
+  ratchet lock ./path/to/file.yaml
+
+  ratchet lock -lockfile ./path/to/.ratchet.lock ./path/to/file.yaml
+
+FLAGS
+
+`
+
+type LockCommand struct {
+	flagConcurrency int64
+	flagParser      string
+	flagLockfile    string
+	flagExclude     stringSliceFlag
+}
+
+func (c *LockCommand) Desc() string {
+	return lockCommandDesc
+}
+
+func (c *LockCommand) Flags() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ExitOnError)
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\n", strings.TrimSpace(lockCommandHelp))
+		f.PrintDefaults()
+	}
+
+	f.Int64Var(&c.flagConcurrency, "concurrency", concurrency.DefaultConcurrency(1),
+		"maximum number of concurrent resolutions")
+	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
+	f.StringVar(&c.flagLockfile, "lockfile", resolver.DefaultLockfile, "path to the lockfile")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
+
+	return f
+}
+
+func (c *LockCommand) Run(ctx context.Context, originalArgs []string) error {
+	args, err := parseFlags(c.Flags(), originalArgs)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	par, err := parser.For(ctx, c.flagParser)
+	if err != nil {
+		return err
+	}
+
+	// Do not use the cache, since the lockfile records when each ref was
+	// resolved.
+	res, err := newResolver(ctx, true, "", 0)
+	if err != nil {
+		return err
+	}
+
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
+	}
+
+	loadResult, err := loadYAMLFiles(fsys, files)
+	if err != nil {
+		return err
+	}
+
+	// Unpin in memory so already-pinned versions are recorded under their
+	// original value. The files themselves are never written.
+	if err := parser.Unpin(ctx, par, loadResult.nodes()); err != nil {
+		return fmt.Errorf("failed to unpin refs: %w", err)
+	}
+
+	lockfile := resolver.NewLockfile()
+	if err := parser.Pin(ctx, lockfile.Record(res), par, loadResult.nodes(), c.flagConcurrency); err != nil {
+		return fmt.Errorf("failed to resolve refs: %w", err)
+	}
+
+	if err := lockfile.Save(c.flagLockfile); err != nil {
+		return fmt.Errorf("failed to save lockfile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package command
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+)
+
+// scalarSnapshot is the state of a scalar node when it was loaded.
+type scalarSnapshot struct {
+	value   string
+	comment string
+	style   yaml.Style
+}
+
+// snapshotScalars records the state of every scalar node in the documents, so
+// changes can be detected and patched into the original contents later.
+func snapshotScalars(documents []*yaml.Node) map[*yaml.Node]*scalarSnapshot {
+	m := make(map[*yaml.Node]*scalarSnapshot, 32)
+	walkScalars(documents, func(node *yaml.Node) {
+		m[node] = &scalarSnapshot{
+			value:   node.Value,
+			comment: node.LineComment,
+			style:   node.Style,
+		}
+	})
+	return m
+}
+
+// walkScalars calls fn for every scalar node in the given nodes.
+func walkScalars(nodes []*yaml.Node, fn func(node *yaml.Node)) {
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if node.Kind == yaml.ScalarNode {
+			fn(node)
+		}
+		walkScalars(node.Content, fn)
+	}
+}
+
+// textEdit replaces the bytes in [start, end) with text.
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// patchYAML splices changed scalar values and line comments into the original
+// contents, leaving every other byte untouched. It returns false if any change
+// cannot be safely patched (e.g. multi-line scalars or structural changes), in
+// which case the caller should re-encode the documents instead.
+func (r *loadResult) patchYAML() (string, bool) {
+	var edits []*textEdit
+	ok := true
+
+	walkScalars(r.documents, func(node *yaml.Node) {
+		if !ok {
+			return
+		}
+
+		snapshot, found := r.scalars[node]
+		if !found {
+			ok = false
+			return
+		}
+
+		if node.Value == snapshot.value && node.LineComment == snapshot.comment {
+			return
+		}
+
+		nodeEdits, patched := patchScalar(r.contents, node, snapshot)
+		if !patched {
+			ok = false
+			return
+		}
+		edits = append(edits, nodeEdits...)
+	})
+	if !ok {
+		return "", false
+	}
+
+	return applyEdits(r.contents, edits)
+}
+
+// patchScalar computes the edits to change the scalar node from its snapshot
+// state to its current state.
+func patchScalar(contents string, node *yaml.Node, snapshot *scalarSnapshot) ([]*textEdit, bool) {
+	start, lineEnd, found := findColumn(contents, node.Line, node.Column)
+	if !found {
+		return nil, false
+	}
+
+	end, found := scalarEnd(contents[start:lineEnd], snapshot)
+	if !found {
+		return nil, false
+	}
+	end += start
+
+	var edits []*textEdit
+
+	if node.Value != snapshot.value {
+		text, found := quoteScalar(node.Value, snapshot.style)
+		if !found {
+			return nil, false
+		}
+		edits = append(edits, &textEdit{start: start, end: end, text: text})
+	}
+
+	if node.LineComment != snapshot.comment {
+		// Mapping keys are followed by a colon, and their comments come after it.
+		if strings.HasPrefix(contents[end:lineEnd], ":") {
+			end++
+		}
+
+		rest := contents[end:lineEnd]
+		trimmed := strings.TrimLeft(rest, " \t")
+		commentStart := end + len(rest) - len(trimmed)
+
+		// Only patch comments if the rest of the line is empty or a comment, and
+		// the original comment is actually on this line.
+		hasComment := strings.HasPrefix(trimmed, "#")
+		if (trimmed != "" && !hasComment) || (snapshot.comment != "" && !hasComment) {
+			return nil, false
+		}
+
+		comment := node.LineComment
+		if comment != "" && !strings.HasPrefix(comment, "#") {
+			comment = "# " + comment
+		}
+
+		switch {
+		case comment == "":
+			edits = append(edits, &textEdit{start: end, end: lineEnd, text: ""})
+		case hasComment:
+			edits = append(edits, &textEdit{start: commentStart, end: lineEnd, text: comment})
+		default:
+			edits = append(edits, &textEdit{start: end, end: end, text: " " + comment})
+		}
+	}
+
+	return edits, true
+}
+
+// findLine returns the byte offsets of the start and end of the given 1-indexed
+// line, excluding the line terminator.
+func findLine(contents string, line int) (int, int, bool) {
+	if line < 1 {
+		return 0, 0, false
+	}
+
+	start := 0
+	for i := 1; i < line; i++ {
+		idx := strings.IndexByte(contents[start:], '\n')
+		if idx < 0 {
+			return 0, 0, false
+		}
+		start += idx + 1
+	}
+
+	end := len(contents)
+	if idx := strings.IndexByte(contents[start:], '\n'); idx >= 0 {
+		end = start + idx
+	}
+	if end > start && contents[end-1] == '\r' {
+		end--
+	}
+	return start, end, true
+}
+
+// findColumn returns the byte offset of the given 1-indexed line and column,
+// and the end of that line. Columns are counted in characters, not bytes.
+func findColumn(contents string, line, column int) (int, int, bool) {
+	lineStart, lineEnd, found := findLine(contents, line)
+	if !found {
+		return 0, 0, false
+	}
+
+	offset := lineStart
+	for col := 1; col < column; col++ {
+		if offset >= lineEnd {
+			return 0, 0, false
+		}
+		_, size := utf8.DecodeRuneInString(contents[offset:lineEnd])
+		offset += size
+	}
+	return offset, lineEnd, true
+}
+
+// scalarEnd returns the length of the scalar token at the start of s, verifying
+// that the token matches the snapshot value.
+func scalarEnd(s string, snapshot *scalarSnapshot) (int, bool) {
+	var expected string
+	switch snapshot.style {
+	case 0:
+		expected = snapshot.value
+	case yaml.SingleQuotedStyle:
+		expected = "'" + strings.ReplaceAll(snapshot.value, "'", "''") + "'"
+	case yaml.DoubleQuotedStyle:
+		if !strings.HasPrefix(s, `"`) {
+			return 0, false
+		}
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1, true
+			}
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+
+	if expected == "" || !strings.HasPrefix(s, expected) {
+		return 0, false
+	}
+	return len(expected), true
+}
+
+// quoteScalar renders the value in the given style. It returns false if the
+// value cannot be safely rendered in that style.
+func quoteScalar(value string, style yaml.Style) (string, bool) {
+	switch style {
+	case 0:
+		if value == "" ||
+			strings.ContainsAny(value, "\n\r\t") ||
+			strings.Contains(value, ": ") ||
+			strings.Contains(value, " #") ||
+			strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@` ") ||
+			strings.HasSuffix(value, " ") || strings.HasSuffix(value, ":") {
+			return "", false
+		}
+		return value, true
+	case yaml.SingleQuotedStyle:
+		if strings.ContainsAny(value, "\n\r") {
+			return "", false
+		}
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", true
+	case yaml.DoubleQuotedStyle:
+		if strings.ContainsAny(value, "\n\r\t") {
+			return "", false
+		}
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		value = strings.ReplaceAll(value, `"`, `\"`)
+		return `"` + value + `"`, true
+	default:
+		return "", false
+	}
+}
+
+// applyEdits applies the edits to the contents. It returns false if any edits
+// overlap.
+func applyEdits(contents string, edits []*textEdit) (string, bool) {
+	slices.SortFunc(edits, func(a, b *textEdit) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		return a.end - b.end
+	})
+
+	var b strings.Builder
+	b.Grow(len(contents))
+
+	last := 0
+	for _, edit := range edits {
+		if edit.start < last {
+			return "", false
+		}
+		b.WriteString(contents[last:edit.start])
+		b.WriteString(edit.text)
+		last = edit.end
+	}
+	b.WriteString(contents[last:])
+
+	return b.String(), true
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sethvargo/ratchet/parser"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+func Test_loadResult_patchYAML(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://actions/checkout@v4": {
+			Resolved: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		"container://ubuntu:20.04": {
+			Resolved: "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
+		},
+		"actions://buildkite-plugins/docker-buildkite-plugin@v5.9.0": {
+			Resolved: "buildkite-plugins/docker-buildkite-plugin@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		parser  parser.Parser
+		in      string
+		exp     string
+		patched bool
+	}{
+		{
+			name: "indentation_and_quotes",
+			in: `jobs:
+    my_job:
+        container:
+            image: "ubuntu:20.04"
+        steps:
+            -   uses: actions/checkout@v4
+            -   uses:   'actions/checkout@v4'   # keep this
+            -   run: echo "{{ not touched }}"
+`,
+			exp: `jobs:
+    my_job:
+        container:
+            image: "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724" # ratchet:ubuntu:20.04
+        steps:
+            -   uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # ratchet:actions/checkout@v4
+            -   uses:   'actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683'   # keep this ratchet:actions/checkout@v4
+            -   run: echo "{{ not touched }}"
+`,
+			patched: true,
+		},
+		{
+			name:    "crlf",
+			in:      "jobs:\r\n  my_job:\r\n    steps:\r\n      - uses: actions/checkout@v4\r\n",
+			exp:     "jobs:\r\n  my_job:\r\n    steps:\r\n      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # ratchet:actions/checkout@v4\r\n",
+			patched: true,
+		},
+		{
+			name:   "mapping_key",
+			parser: new(parser.Buildkite),
+			in: `steps:
+  - plugins:
+      - docker#v5.9.0:  # build
+          image: ubuntu:20.04
+`,
+			exp: `steps:
+  - plugins:
+      - docker#11bd71901bbe5b1630ceea73d27597364c9af683:  # build ratchet:docker#v5.9.0
+          image: ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724 # ratchet:ubuntu:20.04
+`,
+			patched: true,
+		},
+		{
+			name: "flow_mapping",
+			in: `jobs:
+  my_job:
+    steps:
+      - {name: "✅", uses: actions/checkout@v4}
+`,
+			patched: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := fstest.MapFS{
+				"file.yml": &fstest.MapFile{
+					Data: []byte(tc.in),
+				},
+			}
+
+			files, err := loadYAMLFiles(fsys, []string{"file.yml"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := files["file.yml"]
+
+			par := tc.parser
+			if par == nil {
+				par = new(parser.Actions)
+			}
+
+			if err := parser.Pin(ctx, res, par, files.nodes(), 1); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := f.patchYAML()
+			if ok != tc.patched {
+				t.Fatalf("expected patched to be %t, got %t", tc.patched, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("unexpected pin diff (+got, -want):\n%s", diff)
+			}
+
+			if err := parser.Unpin(ctx, par, files.nodes()); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok = f.patchYAML()
+			if !ok {
+				t.Fatal("expected unpin to be patched")
+			}
+			if diff := cmp.Diff(got, tc.in); diff != "" {
+				t.Errorf("unexpected unpin diff (+got, -want):\n%s", diff)
+			}
+		})
+	}
+}
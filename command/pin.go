@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sethvargo/ratchet/internal/concurrency"
 	"github.com/sethvargo/ratchet/parser"
@@ -27,18 +28,34 @@ version. If a version is already pinned, it does nothing.
 
 To update versions that are already pinned, use the "update" command instead.
 
+With -offline, versions are resolved from the lockfile written by the "lock"
+command instead of upstream APIs. Any version missing from the lockfile is an
+error.
+
 EXAMPLES
 
   ratchet pin ./path/to/file.yaml
 
+  ratchet pin -offline ./path/to/file.yaml
+
 FLAGS
 
 `
 
 type PinCommand struct {
-	flagConcurrency int64
-	flagParser      string
-	flagOut         string
+	flagConcurrency     int64
+	flagParser          string
+	flagPreCommitFrozen bool
+	flagOut             string
+	flagReformat        bool
+	flagDryRun          bool
+	flagCheck           bool
+	flagCacheDir        string
+	flagCacheTTL        time.Duration
+	flagNoCache         bool
+	flagOffline         bool
+	flagLockfile        string
+	flagExclude         stringSliceFlag
 }
 
 func (c *PinCommand) Desc() string {
@@ -55,7 +72,26 @@ func (c *PinCommand) Flags() *flag.FlagSet {
 	f.Int64Var(&c.flagConcurrency, "concurrency", concurrency.DefaultConcurrency(1),
 		"maximum number of concurrent resolutions")
 	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
+	f.BoolVar(&c.flagPreCommitFrozen, "precommit-frozen", false,
+		"record pre-commit revisions in pre-commit's \"# frozen: <rev>\" comments")
 	f.StringVar(&c.flagOut, "out", "", "output path (defaults to input file)")
+	f.BoolVar(&c.flagReformat, "reformat", false,
+		"re-encode the entire file instead of only changing updated values")
+	f.BoolVar(&c.flagDryRun, "dry-run", false,
+		"print a diff of the changes instead of writing files")
+	f.BoolVar(&c.flagCheck, "check", false,
+		"print a diff of the changes and exit non-zero if any file would change")
+	f.StringVar(&c.flagCacheDir, "cache-dir", "",
+		"directory for cached resolutions (defaults to the user cache directory)")
+	f.DurationVar(&c.flagCacheTTL, "cache-ttl", resolver.DefaultCacheTTL,
+		"how long cached resolutions are valid")
+	f.BoolVar(&c.flagNoCache, "no-cache", false, "do not read or write cached resolutions")
+	f.BoolVar(&c.flagOffline, "offline", false,
+		"resolve versions from the lockfile instead of upstream APIs")
+	f.StringVar(&c.flagLockfile, "lockfile", resolver.DefaultLockfile,
+		"path to the lockfile used with -offline")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
 
 	return f
 }
@@ -66,17 +102,25 @@ func (c *PinCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	par, err := parser.For(ctx, c.flagParser)
+	par, err := parser.ForWithOptions(ctx, c.flagParser, &parser.Options{
+		PreCommitFrozen: c.flagPreCommitFrozen,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := c.resolver(ctx)
 	if err != nil {
 		return err
 	}
 
-	res, err := resolver.NewDefaultResolver(ctx)
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
 	if err != nil {
-		return fmt.Errorf("failed to create resolver: %w", err)
+		return err
 	}
 
-	loadResult, err := loadYAMLFiles(os.DirFS("."), args)
+	loadResult, err := loadYAMLFiles(fsys, files)
 	if err != nil {
 		return err
 	}
@@ -89,9 +133,23 @@ func (c *PinCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to pin refs: %w", err)
 	}
 
-	if err := loadResult.writeYAMLFiles(c.flagOut); err != nil {
-		return fmt.Errorf("failed to save files: %w", err)
+	if err := loadResult.saveYAMLFiles(c.flagOut, c.flagReformat, c.flagDryRun, c.flagCheck); err != nil {
+		return err
 	}
 
 	return nil
 }
+
+// resolver returns the resolver for the command. In offline mode, this is the
+// lockfile.
+func (c *PinCommand) resolver(ctx context.Context) (resolver.Resolver, error) {
+	if c.flagOffline {
+		lockfile, err := resolver.LoadLockfile(c.flagLockfile)
+		if err != nil {
+			return nil, err
+		}
+		return lockfile, nil
+	}
+
+	return newResolver(ctx, c.flagNoCache, c.flagCacheDir, c.flagCacheTTL)
+}
@@ -35,7 +35,12 @@ FLAGS
 `
 
 type UnpinCommand struct {
-	flagOut string
+	flagParser   string
+	flagOut      string
+	flagReformat bool
+	flagDryRun   bool
+	flagCheck    bool
+	flagExclude  stringSliceFlag
 }
 
 func (c *UnpinCommand) Desc() string {
@@ -49,7 +54,16 @@ func (c *UnpinCommand) Flags() *flag.FlagSet {
 		f.PrintDefaults()
 	}
 
+	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
 	f.StringVar(&c.flagOut, "out", "", "output path (defaults to input file)")
+	f.BoolVar(&c.flagReformat, "reformat", false,
+		"re-encode the entire file instead of only changing updated values")
+	f.BoolVar(&c.flagDryRun, "dry-run", false,
+		"print a diff of the changes instead of writing files")
+	f.BoolVar(&c.flagCheck, "check", false,
+		"print a diff of the changes and exit non-zero if any file would change")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
 
 	return f
 }
@@ -60,7 +74,18 @@ func (c *UnpinCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	loadResult, err := loadYAMLFiles(os.DirFS("."), args)
+	par, err := parser.For(ctx, c.flagParser)
+	if err != nil {
+		return err
+	}
+
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
+	}
+
+	loadResult, err := loadYAMLFiles(fsys, files)
 	if err != nil {
 		return err
 	}
@@ -69,12 +94,12 @@ func (c *UnpinCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("-out must be a directory when pinning multiple files")
 	}
 
-	if err := parser.Unpin(ctx, loadResult.nodes()); err != nil {
+	if err := parser.Unpin(ctx, par, loadResult.nodes()); err != nil {
 		return fmt.Errorf("failed to pin refs: %w", err)
 	}
 
-	if err := loadResult.writeYAMLFiles(c.flagOut); err != nil {
-		return fmt.Errorf("failed to save files: %w", err)
+	if err := loadResult.saveYAMLFiles(c.flagOut, c.flagReformat, c.flagDryRun, c.flagCheck); err != nil {
+		return err
 	}
 
 	return nil
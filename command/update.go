@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/sethvargo/ratchet/parser"
-	"github.com/sethvargo/ratchet/resolver"
 )
 
 const updateCommandDesc = `Update all pinned versions to the latest value`
@@ -55,17 +54,25 @@ func (c *UpdateCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	par, err := parser.For(ctx, c.flagParser)
+	par, err := parser.ForWithOptions(ctx, c.flagParser, &parser.Options{
+		PreCommitFrozen: c.flagPreCommitFrozen,
+	})
 	if err != nil {
 		return err
 	}
 
-	res, err := resolver.NewDefaultResolver(ctx)
+	res, err := c.resolver(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create resolver: %w", err)
+		return err
+	}
+
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
 	}
 
-	loadResult, err := loadYAMLFiles(os.DirFS("."), args)
+	loadResult, err := loadYAMLFiles(fsys, files)
 	if err != nil {
 		return err
 	}
@@ -74,7 +81,7 @@ func (c *UpdateCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("-out must be a directory when pinning multiple files")
 	}
 
-	if err := parser.Unpin(ctx, loadResult.nodes()); err != nil {
+	if err := parser.Unpin(ctx, par, loadResult.nodes()); err != nil {
 		return fmt.Errorf("failed to pin refs: %w", err)
 	}
 
@@ -82,8 +89,8 @@ func (c *UpdateCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to pin refs: %w", err)
 	}
 
-	if err := loadResult.writeYAMLFiles(c.flagOut); err != nil {
-		return fmt.Errorf("failed to save files: %w", err)
+	if err := loadResult.saveYAMLFiles(c.flagOut, c.flagReformat, c.flagDryRun, c.flagCheck); err != nil {
+		return err
 	}
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sethvargo/ratchet/internal/concurrency"
 	"github.com/sethvargo/ratchet/parser"
@@ -33,10 +34,18 @@ FLAGS
 `
 
 type UpgradeCommand struct {
-	flagConcurrency int64
-	flagParser      string
-	flagOut         string
-	flagPin         bool
+	flagConcurrency     int64
+	flagParser          string
+	flagPreCommitFrozen bool
+	flagOut             string
+	flagReformat        bool
+	flagDryRun          bool
+	flagCheck           bool
+	flagCacheDir        string
+	flagCacheTTL        time.Duration
+	flagNoCache         bool
+	flagPin             bool
+	flagExclude         stringSliceFlag
 }
 
 func (c *UpgradeCommand) Desc() string {
@@ -53,8 +62,23 @@ func (c *UpgradeCommand) Flags() *flag.FlagSet {
 	f.Int64Var(&c.flagConcurrency, "concurrency", concurrency.DefaultConcurrency(1),
 		"maximum number of concurrent resolutions")
 	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
+	f.BoolVar(&c.flagPreCommitFrozen, "precommit-frozen", false,
+		"record pre-commit revisions in pre-commit's \"# frozen: <rev>\" comments")
 	f.StringVar(&c.flagOut, "out", "", "output path (defaults to input file)")
+	f.BoolVar(&c.flagReformat, "reformat", false,
+		"re-encode the entire file instead of only changing updated values")
+	f.BoolVar(&c.flagDryRun, "dry-run", false,
+		"print a diff of the changes instead of writing files")
+	f.BoolVar(&c.flagCheck, "check", false,
+		"print a diff of the changes and exit non-zero if any file would change")
+	f.StringVar(&c.flagCacheDir, "cache-dir", "",
+		"directory for cached resolutions (defaults to the user cache directory)")
+	f.DurationVar(&c.flagCacheTTL, "cache-ttl", resolver.DefaultCacheTTL,
+		"how long cached resolutions are valid")
+	f.BoolVar(&c.flagNoCache, "no-cache", false, "do not read or write cached resolutions")
 	f.BoolVar(&c.flagPin, "pin", true, "pin resolved upgraded versions")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
 
 	return f
 }
@@ -65,17 +89,25 @@ func (c *UpgradeCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	par, err := parser.For(ctx, c.flagParser)
+	par, err := parser.ForWithOptions(ctx, c.flagParser, &parser.Options{
+		PreCommitFrozen: c.flagPreCommitFrozen,
+	})
 	if err != nil {
 		return err
 	}
 
-	res, err := resolver.NewDefaultResolver(ctx)
+	res, err := newResolver(ctx, c.flagNoCache, c.flagCacheDir, c.flagCacheTTL)
 	if err != nil {
-		return fmt.Errorf("failed to create resolver: %w", err)
+		return err
+	}
+
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
 	}
 
-	loadResult, err := loadYAMLFiles(os.DirFS("."), args)
+	loadResult, err := loadYAMLFiles(fsys, files)
 	if err != nil {
 		return err
 	}
@@ -84,7 +116,7 @@ func (c *UpgradeCommand) Run(ctx context.Context, originalArgs []string) error {
 		return fmt.Errorf("-out must be a directory when upgrading multiple files")
 	}
 
-	if err := parser.Unpin(ctx, loadResult.nodes()); err != nil {
+	if err := parser.Unpin(ctx, par, loadResult.nodes()); err != nil {
 		return fmt.Errorf("failed to unpin refs: %w", err)
 	}
 
@@ -98,8 +130,8 @@ func (c *UpgradeCommand) Run(ctx context.Context, originalArgs []string) error {
 		}
 	}
 
-	if err := loadResult.writeYAMLFiles(c.flagOut); err != nil {
-		return fmt.Errorf("failed to save files: %w", err)
+	if err := loadResult.saveYAMLFiles(c.flagOut, c.flagReformat, c.flagDryRun, c.flagCheck); err != nil {
+		return err
 	}
 
 	return nil
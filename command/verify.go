@@ -0,0 +1,132 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sethvargo/ratchet/formatter"
+	"github.com/sethvargo/ratchet/internal/concurrency"
+	"github.com/sethvargo/ratchet/parser"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+const verifyCommandDesc = `Verify pinned versions match their ratchet comments`
+
+const verifyCommandHelp = `
+Usage: ratchet verify [FILE...]
+
+The "verify" command resolves the version recorded in the ratchet comment of
+every pinned reference and compares it to the pin:
+
+  actions/checkout@2541b1294d2704b0964813337f... # ratchet:actions/checkout@v4
+
+If the pin matches the resolved version, it is current. If the pin is an
+ancestor of the resolved version, it is reported as stale. Otherwise, the pin
+is unrelated to the comment (for example, from a manual edit or a bad merge)
+and is reported as suspicious. Container digests have no history, so a digest
+that does not match is always reported as suspicious. References without a
+ratchet comment and references with the "ratchet:exclude" comment are ignored.
+
+If any pins are stale or suspicious, it returns a non-zero exit code. This
+command communicates with upstream APIs or services, but it never modifies
+files.
+
+EXAMPLES
+
+  ratchet verify ./path/to/file.yaml
+
+FLAGS
+
+`
+
+type VerifyCommand struct {
+	flagConcurrency int64
+	flagFormat      string
+	flagParser      string
+	flagExclude     stringSliceFlag
+}
+
+func (c *VerifyCommand) Desc() string {
+	return verifyCommandDesc
+}
+
+func (c *VerifyCommand) Flags() *flag.FlagSet {
+	f := flag.NewFlagSet("", flag.ExitOnError)
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\n", strings.TrimSpace(verifyCommandHelp))
+		f.PrintDefaults()
+	}
+
+	format := "human"
+	if v := os.Getenv("GITHUB_ACTIONS"); v != "" {
+		format = "actions"
+	}
+
+	f.Int64Var(&c.flagConcurrency, "concurrency", concurrency.DefaultConcurrency(1),
+		"maximum number of concurrent resolutions")
+	f.StringVar(&c.flagFormat, "format", format, "output format")
+	f.StringVar(&c.flagParser, "parser", "actions", "parser to use")
+	f.Var(&c.flagExclude, "exclude",
+		"glob pattern of paths to exclude (may be given multiple times)")
+
+	return f
+}
+
+func (c *VerifyCommand) Run(ctx context.Context, originalArgs []string) error {
+	args, err := parseFlags(c.Flags(), originalArgs)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	par, err := parser.For(ctx, c.flagParser)
+	if err != nil {
+		return err
+	}
+
+	fmter, err := formatter.For(ctx, c.flagFormat)
+	if err != nil {
+		return err
+	}
+
+	// Cached resolutions are intentionally not used, since the point is to
+	// compare against the current upstream version.
+	res, err := resolver.NewDefaultResolver(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create resolver: %w", err)
+	}
+
+	comparer, ok := res.(resolver.CommitComparer)
+	if !ok {
+		return fmt.Errorf("resolver %T does not support comparing refs", res)
+	}
+
+	fsys := os.DirFS(".")
+	files, err := expandPaths(fsys, args, c.flagExclude)
+	if err != nil {
+		return err
+	}
+
+	loadResult, err := loadYAMLFiles(fsys, files)
+	if err != nil {
+		return err
+	}
+
+	violations, err := parser.Verify(ctx, res, comparer, par, loadResult.nodes(), c.flagConcurrency)
+	if err != nil {
+		return fmt.Errorf("failed to verify refs: %w", err)
+	}
+
+	if err := fmter.Format(os.Stdout, violations); err != nil {
+		return err
+	}
+
+	if l := len(violations); l > 0 {
+		return errors.New("") // empty error to force a non-zero exit code
+	}
+
+	return nil
+}
@@ -10,7 +10,9 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
+	"github.com/sethvargo/ratchet/internal/version"
 	"github.com/sethvargo/ratchet/linter"
 )
 
@@ -26,6 +28,7 @@ var formatterFactory = map[string]Formatter{
 	"json":    FormatterFunc(formatJSON),
 	"lsp":     FormatterFunc(formatLSP),
 	"null":    FormatterFunc(formatNull),
+	"sarif":   FormatterFunc(formatSARIF),
 }
 
 var formatters = sync.OnceValue(func() []string {
@@ -60,10 +63,10 @@ func (f FormatterFunc) Format(w io.Writer, v []*Violation) error {
 func formatActions(w io.Writer, violations []*Violation) error {
 	var merr error
 	for _, v := range violations {
-		message := fmt.Sprintf("%s:%d:%d: The reference `%s` is unpinned. Either pin the reference to a SHA or mark the line with `ratchet:exclude`.",
-			v.Filename, v.Line, v.Column, v.Contents)
-		if _, err := fmt.Fprintf(w, "::error file=%s,line=%d,col=%d,title=Ratchet - Unpinned Reference::%s\n",
-			v.Filename, v.Line, v.Column,
+		message := fmt.Sprintf("%s:%d:%d: %s",
+			v.Filename, v.Line, v.Column, v.Message())
+		if _, err := fmt.Fprintf(w, "::error file=%s,line=%d,col=%d,title=Ratchet - %s::%s\n",
+			v.Filename, v.Line, v.Column, v.RuleOrDefault().Title(),
 			message); err != nil {
 			merr = errors.Join(merr, err)
 		}
@@ -82,8 +85,11 @@ func formatHuman(w io.Writer, violations []*Violation) error {
 	var merr error
 
 	for _, v := range violations {
-		if _, err := fmt.Fprintf(w, "%s:%d:%d: Unpinned reference %q\n",
-			v.Filename, v.Line, v.Column, v.Contents); err != nil {
+		title := strings.ToLower(v.RuleOrDefault().Title())
+		title = strings.ToUpper(title[:1]) + title[1:]
+
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s %q\n",
+			v.Filename, v.Line, v.Column, title, v.Contents); err != nil {
 			merr = errors.Join(merr, err)
 		}
 	}
@@ -110,6 +116,7 @@ func formatJSON(w io.Writer, violations []*Violation) error {
 		Contents string `json:"contents,omitempty"`
 		Line     int    `json:"line,omitempty"`
 		Column   int    `json:"column,omitempty"`
+		Rule     string `json:"rule,omitempty"`
 	}
 
 	list := make([]*InternalJSON, 0, len(violations))
@@ -119,6 +126,7 @@ func formatJSON(w io.Writer, violations []*Violation) error {
 			Contents: v.Contents,
 			Line:     v.Line,
 			Column:   v.Column,
+			Rule:     string(v.RuleOrDefault()),
 		})
 	}
 
@@ -148,8 +156,8 @@ func formatLSP(w io.Writer, violations []*Violation) error {
 	list := make([]*InternalJSON, 0, len(violations))
 	for _, v := range violations {
 		list = append(list, &InternalJSON{
-			Message:  "Reference is unpinned",
-			Code:     "unpinned",
+			Message:  v.RuleOrDefault().Summary(),
+			Code:     string(v.RuleOrDefault()),
 			Severity: "Error",
 			Range: &Range{
 				Start: &Position{
@@ -166,3 +174,146 @@ func formatLSP(w io.Writer, violations []*Violation) error {
 
 	return json.NewEncoder(w).Encode(list)
 }
+
+// formatSARIF formats in the Static Analysis Results Interchange Format (SARIF)
+// version 2.1.0, which can be uploaded to GitHub code scanning and other SARIF
+// consumers.
+func formatSARIF(w io.Writer, violations []*Violation) error {
+	type Message struct {
+		Text string `json:"text"`
+	}
+
+	type Configuration struct {
+		Level string `json:"level"`
+	}
+
+	type Rule struct {
+		ID                   string         `json:"id"`
+		Name                 string         `json:"name"`
+		ShortDescription     *Message       `json:"shortDescription"`
+		FullDescription      *Message       `json:"fullDescription"`
+		HelpURI              string         `json:"helpUri"`
+		DefaultConfiguration *Configuration `json:"defaultConfiguration"`
+	}
+
+	type Driver struct {
+		Name           string  `json:"name"`
+		Version        string  `json:"version"`
+		InformationURI string  `json:"informationUri"`
+		Rules          []*Rule `json:"rules"`
+	}
+
+	type Tool struct {
+		Driver *Driver `json:"driver"`
+	}
+
+	type ArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId"`
+	}
+
+	type Region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndColumn   int `json:"endColumn"`
+	}
+
+	type PhysicalLocation struct {
+		ArtifactLocation *ArtifactLocation `json:"artifactLocation"`
+		Region           *Region           `json:"region"`
+	}
+
+	type Location struct {
+		PhysicalLocation *PhysicalLocation `json:"physicalLocation"`
+	}
+
+	type Result struct {
+		RuleID    string      `json:"ruleId"`
+		RuleIndex int         `json:"ruleIndex"`
+		Level     string      `json:"level"`
+		Message   *Message    `json:"message"`
+		Locations []*Location `json:"locations"`
+	}
+
+	type Run struct {
+		Tool       *Tool     `json:"tool"`
+		ColumnKind string    `json:"columnKind"`
+		Results    []*Result `json:"results"`
+	}
+
+	type Log struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []*Run `json:"runs"`
+	}
+
+	rules := make([]*Rule, 0, len(linter.Rules))
+	ruleIndex := make(map[linter.Rule]int, len(linter.Rules))
+	for i, rule := range linter.Rules {
+		ruleIndex[rule] = i
+		rules = append(rules, &Rule{
+			ID:   string(rule),
+			Name: strings.ReplaceAll(rule.Title(), " ", ""),
+			ShortDescription: &Message{
+				Text: rule.Summary(),
+			},
+			FullDescription: &Message{
+				Text: rule.Description(),
+			},
+			HelpURI: "https://github.com/sethvargo/ratchet#readme",
+			DefaultConfiguration: &Configuration{
+				Level: "error",
+			},
+		})
+	}
+
+	results := make([]*Result, 0, len(violations))
+	for _, v := range violations {
+		rule := v.RuleOrDefault()
+		results = append(results, &Result{
+			RuleID:    string(rule),
+			RuleIndex: ruleIndex[rule],
+			Level:     "error",
+			Message: &Message{
+				Text: v.Message(),
+			},
+			Locations: []*Location{
+				{
+					PhysicalLocation: &PhysicalLocation{
+						ArtifactLocation: &ArtifactLocation{
+							URI:       v.Filename,
+							URIBaseID: "%SRCROOT%",
+						},
+						Region: &Region{
+							StartLine:   v.Line,
+							StartColumn: v.Column,
+							EndColumn:   v.Column + utf8.RuneCountInString(v.Contents),
+						},
+					},
+				},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&Log{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []*Run{
+			{
+				Tool: &Tool{
+					Driver: &Driver{
+						Name:           version.Name,
+						Version:        version.Version,
+						InformationURI: "https://github.com/sethvargo/ratchet",
+						Rules:          rules,
+					},
+				},
+				// YAML columns count characters, not the default UTF-16 code units.
+				ColumnKind: "unicodeCodePoints",
+				Results:    results,
+			},
+		},
+	})
+}
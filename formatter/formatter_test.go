@@ -0,0 +1,203 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sethvargo/ratchet/linter"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	t.Parallel()
+
+	type Region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndColumn   int `json:"endColumn"`
+	}
+
+	type Log struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			ColumnKind string `json:"columnKind"`
+			Results    []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI       string `json:"uri"`
+							URIBaseID string `json:"uriBaseId"`
+						} `json:"artifactLocation"`
+						Region Region `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	cases := []struct {
+		name       string
+		violations []*Violation
+		exp        []Region
+	}{
+		{
+			name:       "no_violations",
+			violations: nil,
+			exp:        nil,
+		},
+		{
+			name: "default_rule",
+			violations: []*Violation{
+				{
+					Filename: ".github/workflows/test.yml",
+					Contents: "actions/checkout@v4",
+					Line:     7,
+					Column:   15,
+				},
+			},
+			exp: []Region{
+				{StartLine: 7, StartColumn: 15, EndColumn: 34},
+			},
+		},
+		{
+			name: "non_ascii",
+			violations: []*Violation{
+				{
+					Filename: ".github/workflows/tëst.yml",
+					Contents: "my-org/äction@v1",
+					Line:     3,
+					Column:   9,
+				},
+			},
+			exp: []Region{
+				{StartLine: 3, StartColumn: 9, EndColumn: 25},
+			},
+		},
+		{
+			name: "multiple_rules",
+			violations: []*Violation{
+				{
+					Filename: ".github/workflows/test.yml",
+					Contents: "actions/checkout@v4",
+					Line:     1,
+					Column:   1,
+					Rule:     linter.RuleUnpinned,
+				},
+				{
+					Filename: "ci/pipeline.yml",
+					Contents: "my-org/action@abcd1234",
+					Line:     12,
+					Column:   9,
+					Rule:     linter.RuleImpostorCommit,
+				},
+				{
+					Filename: "ci/pipeline.yml",
+					Contents: "ubuntu@sha256:abcd",
+					Line:     20,
+					Column:   14,
+					Rule:     linter.RuleSuspiciousPin,
+				},
+			},
+			exp: []Region{
+				{StartLine: 1, StartColumn: 1, EndColumn: 20},
+				{StartLine: 12, StartColumn: 9, EndColumn: 31},
+				{StartLine: 20, StartColumn: 14, EndColumn: 32},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := formatSARIF(&b, tc.violations); err != nil {
+				t.Fatal(err)
+			}
+
+			var log Log
+			if err := json.Unmarshal(b.Bytes(), &log); err != nil {
+				t.Fatalf("failed to decode SARIF: %s\n\n%s", err, b.String())
+			}
+
+			if got, want := log.Version, "2.1.0"; got != want {
+				t.Errorf("expected version %q to be %q", got, want)
+			}
+			if log.Schema == "" {
+				t.Errorf("expected $schema to be set")
+			}
+			if got, want := len(log.Runs), 1; got != want {
+				t.Fatalf("expected %d runs to be %d", got, want)
+			}
+			run := log.Runs[0]
+
+			if got, want := run.ColumnKind, "unicodeCodePoints"; got != want {
+				t.Errorf("expected columnKind %q to be %q", got, want)
+			}
+
+			// Every rule is declared exactly once.
+			rules := run.Tool.Driver.Rules
+			seen := make(map[string]int, len(rules))
+			for i, rule := range rules {
+				if _, ok := seen[rule.ID]; ok {
+					t.Errorf("expected rule %q to be declared once", rule.ID)
+				}
+				seen[rule.ID] = i
+			}
+			for _, rule := range linter.Rules {
+				if _, ok := seen[string(rule)]; !ok {
+					t.Errorf("expected rule %q to be declared", rule)
+				}
+			}
+
+			if got, want := len(run.Results), len(tc.violations); got != want {
+				t.Fatalf("expected %d results to be %d", got, want)
+			}
+			for i, result := range run.Results {
+				v := tc.violations[i]
+
+				if got, want := result.RuleID, string(v.RuleOrDefault()); got != want {
+					t.Errorf("result %d: expected ruleId %q to be %q", i, got, want)
+				}
+				if result.RuleIndex < 0 || result.RuleIndex >= len(rules) {
+					t.Fatalf("result %d: ruleIndex %d is out of range", i, result.RuleIndex)
+				}
+				if got, want := rules[result.RuleIndex].ID, result.RuleID; got != want {
+					t.Errorf("result %d: expected rule at ruleIndex to be %q, got %q", i, want, got)
+				}
+				if got, want := result.Message.Text, v.Message(); got != want {
+					t.Errorf("result %d: expected message %q to be %q", i, got, want)
+				}
+
+				if got, want := len(result.Locations), 1; got != want {
+					t.Fatalf("result %d: expected %d locations to be %d", i, got, want)
+				}
+				loc := result.Locations[0].PhysicalLocation
+				if got, want := loc.ArtifactLocation.URI, v.Filename; got != want {
+					t.Errorf("result %d: expected uri %q to be %q", i, got, want)
+				}
+				if got, want := loc.ArtifactLocation.URIBaseID, "%SRCROOT%"; got != want {
+					t.Errorf("result %d: expected uriBaseId %q to be %q", i, got, want)
+				}
+				if got, want := loc.Region, tc.exp[i]; got != want {
+					t.Errorf("result %d: expected region %+v to be %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package diff produces unified diffs between two texts.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// op is a single line in an edit script.
+type op struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// Unified returns a unified diff that transforms a into b, using the given
+// names in the header. It returns the empty string if a and b are equal.
+func Unified(oldName, newName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := editScript(splitLines(a), splitLines(b))
+
+	var w strings.Builder
+	fmt.Fprintf(&w, "--- %s\n", oldName)
+	fmt.Fprintf(&w, "+++ %s\n", newName)
+
+	// Line numbers (1-indexed) in a and b at the start of each op.
+	aLine, bLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	aLine[0], bLine[0] = 1, 1
+	for i, o := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if o.kind != '+' {
+			aLine[i+1]++
+		}
+		if o.kind != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Find the end of this hunk, merging changes that are separated by less
+		// than twice the context.
+		start := max(i-contextLines, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+				continue
+			}
+			if j-end >= 2*contextLines {
+				break
+			}
+		}
+		end = min(end+contextLines, len(ops))
+
+		aCount := aLine[end] - aLine[start]
+		bCount := bLine[end] - bLine[start]
+		aStart, bStart := aLine[start], bLine[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+
+		fmt.Fprintf(&w, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, o := range ops[start:end] {
+			w.WriteByte(o.kind)
+			w.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				w.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return w.String()
+}
+
+// splitLines splits s into lines, keeping the line terminators.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// editScript computes the shortest edit script from a to b. Common prefixes and
+// suffixes are trimmed first, since most changes only touch a few lines.
+func editScript(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{kind: ' ', line: line})
+	}
+
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{kind: ' ', line: line})
+	}
+
+	return ops
+}
+
+// myers computes the shortest edit script from a to b using Myers' greedy
+// algorithm, which takes O((n+m)·d) time for d inserted and deleted lines. Only
+// the furthest reaching x on each diagonal is kept for every d, so it needs
+// O(d²) memory instead of a full n×m table.
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+
+	// trace[d][k+d] is the furthest x on diagonal k (x-y) reached with d edits.
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		v := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			switch {
+			case d == 0:
+				x = 0
+			case insertion(trace[d-1], d, k):
+				x = trace[d-1][k+1+d-1]
+			default:
+				x = trace[d-1][k-1+d-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+d] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, v)
+				break search
+			}
+		}
+		trace = append(trace, v)
+	}
+
+	// Walk back from the end, emitting the ops in reverse.
+	ops := make([]op, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		k := x - y
+
+		prevK := k - 1
+		if insertion(trace[d-1], d, k) {
+			prevK = k + 1
+		}
+		prevX := trace[d-1][prevK+d-1]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, op{kind: ' ', line: a[x-1]})
+			x--
+			y--
+		}
+
+		if prevK == k+1 {
+			ops = append(ops, op{kind: '+', line: b[y-1]})
+			y--
+		} else {
+			ops = append(ops, op{kind: '-', line: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, op{kind: ' ', line: a[x-1]})
+		x--
+		y--
+	}
+
+	slices.Reverse(ops)
+	return ops
+}
+
+// insertion returns true if diagonal k at edit distance d is best reached by
+// inserting a line from b (moving down from diagonal k+1), rather than deleting
+// a line from a (moving right from diagonal k-1). prev is the trace for d-1.
+func insertion(prev []int, d, k int) bool {
+	return k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1])
+}
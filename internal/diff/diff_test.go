@@ -0,0 +1,193 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    string
+		b    string
+		exp  string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			exp:  "",
+		},
+		{
+			name: "change",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			exp: `--- a/file.yml
++++ b/file.yml
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			name: "separate_hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			exp: `--- a/file.yml
++++ b/file.yml
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`,
+		},
+		{
+			name: "insert_and_delete",
+			a:    "a\nb\nc\n",
+			b:    "a\nc\nd\n",
+			exp: `--- a/file.yml
++++ b/file.yml
+@@ -1,3 +1,3 @@
+ a
+-b
+ c
++d
+`,
+		},
+		{
+			name: "from_empty",
+			a:    "",
+			b:    "a\n",
+			exp: `--- a/file.yml
++++ b/file.yml
+@@ -0,0 +1,1 @@
++a
+`,
+		},
+		{
+			name: "no_trailing_newline",
+			a:    "a\nb",
+			b:    "a\nc",
+			exp: `--- a/file.yml
++++ b/file.yml
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++c
+\ No newline at end of file
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Unified("a/file.yml", "b/file.yml", tc.a, tc.b)
+			if diff := cmp.Diff(got, tc.exp); diff != "" {
+				t.Errorf("unexpected diff (+got, -want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEditScript(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		a     string
+		b     string
+		edits int
+	}{
+		{
+			name:  "empty",
+			a:     "",
+			b:     "",
+			edits: 0,
+		},
+		{
+			name:  "all_inserted",
+			a:     "",
+			b:     "abc",
+			edits: 3,
+		},
+		{
+			name:  "all_deleted",
+			a:     "abc",
+			b:     "",
+			edits: 3,
+		},
+		{
+			name:  "interleaved",
+			a:     "abcabba",
+			b:     "cbabac",
+			edits: 5,
+		},
+		{
+			name:  "replaced",
+			a:     "abcd",
+			b:     "wxyz",
+			edits: 8,
+		},
+		{
+			name:  "moved_block",
+			a:     "abcdefgh",
+			b:     "efghabcd",
+			edits: 8,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := strings.Split(tc.a, ""), strings.Split(tc.b, "")
+			ops := editScript(a, b)
+
+			var gotA, gotB []string
+			var edits int
+			for _, o := range ops {
+				if o.kind != '+' {
+					gotA = append(gotA, o.line)
+				}
+				if o.kind != '-' {
+					gotB = append(gotB, o.line)
+				}
+				if o.kind != ' ' {
+					edits++
+				}
+			}
+
+			if diff := cmp.Diff(a, gotA, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("script does not reproduce a (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(b, gotB, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("script does not reproduce b (-want, +got):\n%s", diff)
+			}
+			if got, want := edits, tc.edits; got != want {
+				t.Errorf("expected %d edits to be %d", got, want)
+			}
+		})
+	}
+}
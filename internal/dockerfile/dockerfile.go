@@ -0,0 +1,357 @@
+// Package dockerfile decodes Dockerfiles into YAML nodes, so they can be
+// processed by the same parsers and commands as YAML files.
+//
+// A Dockerfile is decoded into a document with a single "dockerfile" key that
+// holds the list of instructions:
+//
+//	dockerfile:
+//	  - instruction: COPY
+//	    flags:
+//	      from: alpine:3.20
+//	    arguments: [/bin/sh, /bin/sh]
+//
+// Every flag value and argument is a scalar node with the line and column of
+// the token in the original file, so changes can be patched back into it. A
+// comment containing "ratchet:" on the line directly above an instruction is
+// attached as the line comment of the instruction's image (the first argument
+// of FROM, or the --from flag of COPY and ADD).
+package dockerfile
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+)
+
+const (
+	// KeyDockerfile is the key of the list of instructions in the document.
+	KeyDockerfile = "dockerfile"
+
+	// KeyInstruction is the key of the upper-cased instruction name (e.g.
+	// "FROM").
+	KeyInstruction = "instruction"
+
+	// KeyFlags is the key of the mapping of flag names to values (e.g.
+	// "--from=alpine" is "from: alpine").
+	KeyFlags = "flags"
+
+	// KeyArguments is the key of the list of arguments after the flags.
+	KeyArguments = "arguments"
+)
+
+// IsDockerfile returns true if the file at the given path is a Dockerfile,
+// based on its name: "Dockerfile", "Containerfile", "Dockerfile.*" or
+// "*.Dockerfile", ignoring case. Per-Dockerfile ignore files (e.g.
+// "Dockerfile.dockerignore") are not Dockerfiles.
+func IsDockerfile(pth string) bool {
+	base := strings.ToLower(path.Base(strings.ReplaceAll(pth, "\\", "/")))
+	if strings.HasSuffix(base, ".dockerignore") {
+		return false
+	}
+	for _, name := range []string{"dockerfile", "containerfile"} {
+		if base == name ||
+			strings.HasPrefix(base, name+".") ||
+			strings.HasSuffix(base, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Instructions returns the instruction nodes of a document returned by Decode.
+// It returns nil for any other document.
+func Instructions(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.DocumentNode || len(node.Content) == 0 {
+		return nil
+	}
+
+	docMap := node.Content[0]
+	if docMap.Kind != yaml.MappingNode || len(docMap.Content) != 2 ||
+		docMap.Content[0].Value != KeyDockerfile || docMap.Content[1].Kind != yaml.SequenceNode {
+		return nil
+	}
+	return docMap.Content[1].Content
+}
+
+// token is a whitespace-separated word in the Dockerfile and its position.
+type token struct {
+	value  string
+	line   int
+	column int
+}
+
+// Decode decodes the Dockerfile contents into a document node. Line
+// continuations, comments, and heredocs are handled, but variables are not
+// expanded.
+func Decode(contents []byte) (*yaml.Node, error) {
+	lines := strings.Split(string(contents), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	escape, i := parseDirectives(lines)
+
+	instructions := &yaml.Node{Kind: yaml.SequenceNode}
+	var comment string
+
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " \t")
+		if trimmed == "" {
+			comment = ""
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			comment = ""
+			if strings.Contains(trimmed, "ratchet:") {
+				comment = strings.TrimRight(trimmed, " \t")
+			}
+			continue
+		}
+
+		// Collect the tokens from the instruction and its continuation lines.
+		// Comments and empty lines inside of a continuation are ignored.
+		var tokens []token
+		for {
+			line, continued := cutContinuation(lines[i], escape)
+			tokens = append(tokens, tokenize(line, i+1)...)
+			if !continued {
+				break
+			}
+
+			for i+1 < len(lines) {
+				next := strings.TrimLeft(lines[i+1], " \t")
+				if next != "" && !strings.HasPrefix(next, "#") {
+					break
+				}
+				i++
+			}
+			if i+1 >= len(lines) {
+				break
+			}
+			i++
+		}
+
+		if len(tokens) == 0 {
+			comment = ""
+			continue
+		}
+
+		instruction := buildInstruction(tokens, comment)
+		instructions.Content = append(instructions.Content, instruction)
+		comment = ""
+
+		// Skip the bodies of any heredocs.
+		for _, heredoc := range heredocs(tokens) {
+			for {
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated heredoc %q", instruction.Line, heredoc.delimiter)
+				}
+				line := lines[i]
+				if heredoc.stripTabs {
+					line = strings.TrimLeft(line, "\t")
+				}
+				if line == heredoc.delimiter {
+					break
+				}
+			}
+		}
+	}
+
+	return &yaml.Node{
+		Kind: yaml.DocumentNode,
+		Content: []*yaml.Node{
+			{
+				Kind: yaml.MappingNode,
+				Content: []*yaml.Node{
+					scalar(KeyDockerfile, 0, 0),
+					instructions,
+				},
+			},
+		},
+	}, nil
+}
+
+// parseDirectives parses the parser directives at the top of the file. It
+// returns the escape character and the index of the first line after the
+// directives.
+func parseDirectives(lines []string) (rune, int) {
+	escape := '\\'
+
+	for i, line := range lines {
+		body, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+		if !ok {
+			return escape, i
+		}
+
+		key, value, ok := strings.Cut(body, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsFunc(key, unicode.IsSpace) {
+			return escape, i
+		}
+
+		if strings.EqualFold(key, "escape") {
+			switch strings.TrimSpace(value) {
+			case "`":
+				escape = '`'
+			case `\`:
+				escape = '\\'
+			}
+		}
+	}
+	return escape, len(lines)
+}
+
+// cutContinuation removes the trailing escape character from the line. It
+// returns true if the line was continued.
+func cutContinuation(line string, escape rune) (string, bool) {
+	trimmed := strings.TrimRight(line, " \t")
+	if before, ok := strings.CutSuffix(trimmed, string(escape)); ok {
+		return before, true
+	}
+	return line, false
+}
+
+// tokenize splits the line into whitespace-separated tokens. Columns are
+// counted in characters, starting at 1.
+func tokenize(line string, lineNum int) []token {
+	var tokens []token
+	var current *token
+
+	column := 0
+	for offset := 0; offset < len(line); {
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		column++
+
+		if unicode.IsSpace(r) {
+			if current != nil {
+				tokens = append(tokens, *current)
+				current = nil
+			}
+		} else {
+			if current == nil {
+				current = &token{line: lineNum, column: column}
+			}
+			current.value += line[offset : offset+size]
+		}
+		offset += size
+	}
+	if current != nil {
+		tokens = append(tokens, *current)
+	}
+	return tokens
+}
+
+// buildInstruction builds the node for the instruction from its tokens,
+// attaching the ratchet comment to the instruction's image, if any.
+func buildInstruction(tokens []token, comment string) *yaml.Node {
+	keyword := tokens[0]
+	name := strings.ToUpper(keyword.value)
+
+	node := &yaml.Node{
+		Kind:   yaml.MappingNode,
+		Line:   keyword.line,
+		Column: keyword.column,
+		Content: []*yaml.Node{
+			scalar(KeyInstruction, 0, 0),
+			scalar(name, keyword.line, keyword.column),
+		},
+	}
+
+	rest := tokens[1:]
+
+	flags := &yaml.Node{Kind: yaml.MappingNode}
+	for len(rest) > 0 && strings.HasPrefix(rest[0].value, "--") {
+		tok := rest[0]
+		rest = rest[1:]
+
+		key, value, _ := strings.Cut(strings.TrimPrefix(tok.value, "--"), "=")
+		column := tok.column + utf8.RuneCountInString("--"+key+"=")
+		flags.Content = append(flags.Content,
+			scalar(key, tok.line, tok.column+2),
+			scalar(value, tok.line, column))
+	}
+
+	arguments := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, tok := range rest {
+		arguments.Content = append(arguments.Content, scalar(tok.value, tok.line, tok.column))
+	}
+
+	node.Content = append(node.Content,
+		scalar(KeyFlags, 0, 0), flags,
+		scalar(KeyArguments, 0, 0), arguments)
+
+	if comment != "" {
+		var image *yaml.Node
+		switch name {
+		case "FROM":
+			if len(arguments.Content) > 0 {
+				image = arguments.Content[0]
+			}
+		case "COPY", "ADD":
+			for i := 0; i+1 < len(flags.Content); i += 2 {
+				if flags.Content[i].Value == "from" {
+					image = flags.Content[i+1]
+				}
+			}
+		}
+		if image != nil {
+			image.LineComment = comment
+		}
+	}
+
+	return node
+}
+
+// heredoc is a heredoc started by an instruction.
+type heredoc struct {
+	delimiter string
+	stripTabs bool
+}
+
+// heredocs returns the heredocs started by the instruction, in order.
+func heredocs(tokens []token) []*heredoc {
+	switch strings.ToUpper(tokens[0].value) {
+	case "RUN", "COPY", "ADD":
+	default:
+		return nil
+	}
+
+	var result []*heredoc
+	for _, tok := range tokens[1:] {
+		word, ok := strings.CutPrefix(tok.value, "<<")
+		if !ok || strings.HasPrefix(word, "<") {
+			continue
+		}
+
+		word, stripTabs := strings.CutPrefix(word, "-")
+		word = strings.Trim(word, `"'`)
+		if word == "" || strings.ContainsFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+		}) {
+			continue
+		}
+
+		result = append(result, &heredoc{
+			delimiter: word,
+			stripTabs: stripTabs,
+		})
+	}
+	return result
+}
+
+func scalar(value string, line, column int) *yaml.Node {
+	return &yaml.Node{
+		Kind:   yaml.ScalarNode,
+		Tag:    "!!str",
+		Value:  value,
+		Line:   line,
+		Column: column,
+	}
+}
@@ -0,0 +1,306 @@
+package dockerfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+)
+
+func TestIsDockerfile(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  bool
+	}{
+		{name: "dockerfile", in: "Dockerfile", exp: true},
+		{name: "nested", in: "build/Dockerfile", exp: true},
+		{name: "suffix", in: "build/app.Dockerfile", exp: true},
+		{name: "prefix", in: "Dockerfile.dev", exp: true},
+		{name: "containerfile", in: "Containerfile", exp: true},
+		{name: "lowercase", in: "dockerfile", exp: true},
+		{name: "windows", in: `build\Dockerfile`, exp: true},
+		{name: "yaml", in: "action.yml", exp: false},
+		{name: "dockerignore", in: ".dockerignore", exp: false},
+		{name: "dockerfile_dockerignore", in: "app.Dockerfile.dockerignore", exp: false},
+		{name: "contains", in: "mydockerfiles", exp: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := IsDockerfile(tc.in), tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+// instruction is a simplified view of a decoded instruction.
+type instruction struct {
+	Line      int
+	Name      string
+	Flags     map[string]string
+	Arguments []string
+	Comments  []string
+}
+
+func simplify(tb testing.TB, doc *yaml.Node) []*instruction {
+	tb.Helper()
+
+	var result []*instruction
+	for _, node := range Instructions(doc) {
+		inst := &instruction{Line: node.Line}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			switch node.Content[i].Value {
+			case KeyInstruction:
+				inst.Name = value.Value
+			case KeyFlags:
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					if inst.Flags == nil {
+						inst.Flags = make(map[string]string)
+					}
+					inst.Flags[value.Content[j].Value] = value.Content[j+1].Value
+					if c := value.Content[j+1].LineComment; c != "" {
+						inst.Comments = append(inst.Comments, c)
+					}
+				}
+			case KeyArguments:
+				for _, arg := range value.Content {
+					inst.Arguments = append(inst.Arguments, arg.Value)
+					if c := arg.LineComment; c != "" {
+						inst.Comments = append(inst.Comments, c)
+					}
+				}
+			}
+		}
+		result = append(result, inst)
+	}
+	return result
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []*instruction
+		err  string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			exp:  nil,
+		},
+		{
+			name: "instructions",
+			in: `
+# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.24
+from --platform=$BUILDPLATFORM golang:${GO_VERSION} AS build
+COPY --from=alpine:3.20 --chmod=755 /bin/sh /bin/sh
+`,
+			exp: []*instruction{
+				{
+					Line:      3,
+					Name:      "ARG",
+					Arguments: []string{"GO_VERSION=1.24"},
+				},
+				{
+					Line:      4,
+					Name:      "FROM",
+					Flags:     map[string]string{"platform": "$BUILDPLATFORM"},
+					Arguments: []string{"golang:${GO_VERSION}", "AS", "build"},
+				},
+				{
+					Line:      5,
+					Name:      "COPY",
+					Flags:     map[string]string{"from": "alpine:3.20", "chmod": "755"},
+					Arguments: []string{"/bin/sh", "/bin/sh"},
+				},
+			},
+		},
+		{
+			name: "comments",
+			in: `
+# ratchet:ubuntu:24.04
+FROM ubuntu@sha256:abc
+# ratchet:exclude
+COPY --from=alpine:3.20 /a /b
+# ratchet:exclude
+
+RUN echo
+# not ratchet
+FROM scratch
+`,
+			exp: []*instruction{
+				{
+					Line:      3,
+					Name:      "FROM",
+					Arguments: []string{"ubuntu@sha256:abc"},
+					Comments:  []string{"# ratchet:ubuntu:24.04"},
+				},
+				{
+					Line:      5,
+					Name:      "COPY",
+					Flags:     map[string]string{"from": "alpine:3.20"},
+					Arguments: []string{"/a", "/b"},
+					Comments:  []string{"# ratchet:exclude"},
+				},
+				{
+					Line:      8,
+					Name:      "RUN",
+					Arguments: []string{"echo"},
+				},
+				{
+					Line:      10,
+					Name:      "FROM",
+					Arguments: []string{"scratch"},
+				},
+			},
+		},
+		{
+			name: "continuations",
+			in: `
+FROM \
+  # a comment
+
+  ubuntu:24.04 \
+  AS base
+`,
+			exp: []*instruction{
+				{
+					Line:      2,
+					Name:      "FROM",
+					Arguments: []string{"ubuntu:24.04", "AS", "base"},
+				},
+			},
+		},
+		{
+			name: "escape_directive",
+			in:   "# escape=`\nFROM `\n  mcr.microsoft.com/windows/servercore:ltsc2022\nRUN dir c:\\\n",
+			exp: []*instruction{
+				{
+					Line:      2,
+					Name:      "FROM",
+					Arguments: []string{"mcr.microsoft.com/windows/servercore:ltsc2022"},
+				},
+				{
+					Line:      4,
+					Name:      "RUN",
+					Arguments: []string{`dir`, `c:\`},
+				},
+			},
+		},
+		{
+			name: "heredocs",
+			in: `
+FROM ubuntu
+RUN <<EOF cat <<-'END'
+FROM not-an-image
+EOF
+	COPY --from=not-an-image
+	END
+COPY --from=alpine /a /b
+`,
+			exp: []*instruction{
+				{
+					Line:      2,
+					Name:      "FROM",
+					Arguments: []string{"ubuntu"},
+				},
+				{
+					Line:      3,
+					Name:      "RUN",
+					Arguments: []string{"<<EOF", "cat", "<<-'END'"},
+				},
+				{
+					Line:      8,
+					Name:      "COPY",
+					Flags:     map[string]string{"from": "alpine"},
+					Arguments: []string{"/a", "/b"},
+				},
+			},
+		},
+		{
+			name: "only_escape",
+			in: `
+\
+FROM ubuntu
+`,
+			exp: []*instruction{
+				{
+					Line:      3,
+					Name:      "FROM",
+					Arguments: []string{"ubuntu"},
+				},
+			},
+		},
+		{
+			name: "unterminated_heredoc",
+			in: `
+RUN <<EOF
+echo
+`,
+			err: `unterminated heredoc "EOF"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Decode([]byte(tc.in))
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+					t.Errorf("expected %q to contain %q", got, want)
+				}
+				return
+			} else if tc.err != "" {
+				t.Fatal("expected error")
+			}
+
+			if diff := cmp.Diff(tc.exp, simplify(t, doc)); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecode_positions(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Decode([]byte("FROM --platform=linux/amd64 \\\n  golang:1.24 AS build\nCOPY --from=alpine /a /b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instructions := Instructions(doc)
+	if got, want := len(instructions), 2; got != want {
+		t.Fatalf("expected %d to be %d", got, want)
+	}
+
+	// FROM arguments
+	image := instructions[0].Content[5].Content[0]
+	if got, want := [2]int{image.Line, image.Column}, [2]int{2, 3}; got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+
+	// COPY --from value
+	from := instructions[1].Content[3].Content[1]
+	if got, want := [2]int{from.Line, from.Column}, [2]int{3, 13}; got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+}
@@ -1,9 +1,116 @@
 package linter
 
+import (
+	"fmt"
+)
+
+// Rule is the identifier of a linting rule.
+type Rule string
+
+const (
+	// RuleUnpinned is reported for references that are not pinned to an absolute
+	// version.
+	RuleUnpinned Rule = "unpinned"
+
+	// RuleImpostorCommit is reported for pinned commits that are not reachable
+	// from any branch or tag of the referenced repository. GitHub serves commits
+	// from forks under the parent repository's name, so these commits may not
+	// come from the upstream repository at all.
+	RuleImpostorCommit Rule = "impostor-commit"
+
+	// RuleStalePin is reported for pinned references that are older than the
+	// version recorded in their ratchet comment.
+	RuleStalePin Rule = "stale-pin"
+
+	// RuleSuspiciousPin is reported for pinned references that are unrelated to
+	// the version recorded in their ratchet comment, which usually means the pin
+	// or the comment was edited by hand.
+	RuleSuspiciousPin Rule = "suspicious-pin"
+)
+
+// Rules is the list of all rules.
+var Rules = []Rule{
+	RuleUnpinned,
+	RuleImpostorCommit,
+	RuleStalePin,
+	RuleSuspiciousPin,
+}
+
+// Title returns the human-readable title of the rule.
+func (r Rule) Title() string {
+	switch r {
+	case RuleImpostorCommit:
+		return "Impostor Commit"
+	case RuleStalePin:
+		return "Stale Pin"
+	case RuleSuspiciousPin:
+		return "Suspicious Pin"
+	default:
+		return "Unpinned Reference"
+	}
+}
+
+// Summary returns a short sentence describing the violation of the rule.
+func (r Rule) Summary() string {
+	switch r {
+	case RuleImpostorCommit:
+		return "Commit is not reachable from the repository"
+	case RuleStalePin:
+		return "Pin is older than its ratchet comment"
+	case RuleSuspiciousPin:
+		return "Pin does not match its ratchet comment"
+	default:
+		return "Reference is unpinned"
+	}
+}
+
+// Description returns the long description of the rule.
+func (r Rule) Description() string {
+	switch r {
+	case RuleImpostorCommit:
+		return "Pinned commits should be reachable from a branch or tag of the referenced repository. GitHub serves commits from forks under the parent repository's name, so an unreachable commit may come from an untrusted fork."
+	case RuleStalePin:
+		return "Pinned references should match the version recorded in their ratchet comment. A pin that is older than the comment was not updated after the upstream version moved."
+	case RuleSuspiciousPin:
+		return "Pinned references should match the version recorded in their ratchet comment. A pin that is unrelated to the comment was likely edited by hand or changed in a bad merge, and should be reviewed."
+	default:
+		return "References to actions, containers, and other upstream dependencies should be pinned to an absolute SHA or digest, since tags and labels are mutable."
+	}
+}
+
 // Violation represents an instance of a linting violation.
 type Violation struct {
 	Filename string
 	Contents string
 	Line     int
 	Column   int
+
+	// Rule is the rule that was violated. If empty, it is [RuleUnpinned].
+	Rule Rule
+}
+
+// RuleOrDefault returns the violated rule, defaulting to [RuleUnpinned].
+func (v *Violation) RuleOrDefault() Rule {
+	if v.Rule == "" {
+		return RuleUnpinned
+	}
+	return v.Rule
+}
+
+// Message returns the detailed message for the violation.
+func (v *Violation) Message() string {
+	switch v.RuleOrDefault() {
+	case RuleImpostorCommit:
+		return fmt.Sprintf("The commit in `%s` is not reachable from any branch or tag of the repository. It may be an impostor commit from a fork.",
+			v.Contents)
+	case RuleStalePin:
+		return fmt.Sprintf("The reference `%s` is older than the version in its ratchet comment. Run `ratchet update` to re-pin it.",
+			v.Contents)
+	case RuleSuspiciousPin:
+		return fmt.Sprintf("The reference `%s` does not match the version in its ratchet comment and is not an ancestor of it. Review the pin and the comment.",
+			v.Contents)
+	default:
+		return fmt.Sprintf("The reference `%s` is unpinned. Either pin the reference to a SHA or mark the line with `ratchet:exclude`.",
+			v.Contents)
+	}
 }
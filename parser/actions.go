@@ -58,15 +58,21 @@ func (a *Actions) parseOne(refs *RefsList, node *yaml.Node) error {
 					continue
 				}
 
-				// Only look at composite actions.
-				foundComposite := false
-				for j, runMap := range runs.Content {
-					if runMap.Value == "using" && len(runs.Content) > j+1 && runs.Content[j+1].Value == "composite" {
-						foundComposite = true
-						break
+				// Docker container actions may run a published image instead of a
+				// Dockerfile in the repository.
+				using := mappingValue(runs, "using")
+				if using != nil && using.Value == "docker" {
+					image := mappingValue(runs, "image")
+					if image != nil && image.Kind == yaml.ScalarNode &&
+						strings.HasPrefix(image.Value, "docker://") && !strings.Contains(image.Value, "${{") {
+						ref := resolver.NormalizeContainerRef(image.Value)
+						refs.Add(ref, image)
 					}
+					continue
 				}
-				if !foundComposite {
+
+				// Otherwise, only look at composite actions.
+				if using == nil || using.Value != "composite" {
 					continue
 				}
 
@@ -117,11 +123,25 @@ func (a *Actions) parseOne(refs *RefsList, node *yaml.Node) error {
 						continue
 					}
 
-					for j, sub := range jobMap.Content {
+					// Only look at keys, since values (e.g. "name: container") may
+					// look like keywords.
+					for j := 0; j+1 < len(jobMap.Content); j += 2 {
+						sub := jobMap.Content[j]
+
 						// Container reference for running the job, should be resolved as a
 						// Docker reference.
 						if sub.Value == "container" {
 							containerMap := jobMap.Content[j+1]
+
+							// The container may be just the image (e.g. "container: node:18").
+							if containerMap.Kind == yaml.ScalarNode {
+								if containerMap.Value != "" && !strings.Contains(containerMap.Value, "${{") {
+									ref := resolver.NormalizeContainerRef(containerMap.Value)
+									refs.Add(ref, containerMap)
+								}
+								continue
+							}
+
 							for k, property := range containerMap.Content {
 								if property.Value == "image" {
 									image := containerMap.Content[k+1]
@@ -143,7 +163,22 @@ func (a *Actions) parseOne(refs *RefsList, node *yaml.Node) error {
 						// This is a map, so the container value is nested a bit deeper.
 						if sub.Value == "services" {
 							servicesMap := jobMap.Content[j+1]
-							for _, subMap := range servicesMap.Content {
+							if servicesMap.Kind != yaml.MappingNode {
+								continue
+							}
+
+							for l := 1; l < len(servicesMap.Content); l += 2 {
+								subMap := servicesMap.Content[l]
+
+								// The service may be just the image (e.g. "redis: redis:7").
+								if subMap.Kind == yaml.ScalarNode {
+									if subMap.Value != "" && !strings.Contains(subMap.Value, "${{") {
+										ref := resolver.NormalizeContainerRef(subMap.Value)
+										refs.Add(ref, subMap)
+									}
+									continue
+								}
+
 								if subMap.Kind != yaml.MappingNode {
 									continue
 								}
@@ -57,6 +57,31 @@ jobs:
 				"container://ubuntu:20.04",
 			},
 		},
+		{
+			name: "container_string",
+			in: `
+jobs:
+  my_job:
+    container: 'node:18'
+`,
+			exp: []string{
+				"container://node:18",
+			},
+		},
+		{
+			name: "job_named_container",
+			in: `
+jobs:
+  my_job:
+    name: 'container'
+    runs-on: 'ubuntu-latest'
+    steps:
+      - uses: 'actions/checkout@v3'
+`,
+			exp: []string{
+				"actions://actions/checkout@v3",
+			},
+		},
 		{
 			name: "services",
 			in: `
@@ -73,6 +98,43 @@ jobs:
 				"container://ubuntu:20.04",
 			},
 		},
+		{
+			name: "services_string",
+			in: `
+jobs:
+  my_job:
+    services:
+      redis: 'redis:7'
+      postgres:
+        image: 'postgres:16'
+`,
+			exp: []string{
+				"container://postgres:16",
+				"container://redis:7",
+			},
+		},
+		{
+			name: "docker_action",
+			in: `
+runs:
+  using: 'docker'
+  image: 'docker://alpine:3.20'
+  args:
+    - 'hello'
+`,
+			exp: []string{
+				"container://alpine:3.20",
+			},
+		},
+		{
+			name: "docker_action_dockerfile",
+			in: `
+runs:
+  using: 'docker'
+  image: 'Dockerfile'
+`,
+			exp: nil,
+		},
 		{
 			name: "composite",
 			in: `
@@ -98,8 +160,12 @@ jobs:
   my_job:
     container:
       image: 'ghcr.io/${{ github.repository }}/container:1.2.3'
+    services:
+      cache: '${{ matrix.cache }}'
     steps:
       - uses: 'actions/${{ github.sha }}'
+  other_job:
+    container: '${{ matrix.image }}'
 
 `,
 			exp: nil,
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+// argoWorkflowSpecPaths is the path to the workflow spec for each Argo
+// Workflows kind.
+var argoWorkflowSpecPaths = map[string][]string{
+	"Workflow":                {"spec"},
+	"WorkflowTemplate":        {"spec"},
+	"ClusterWorkflowTemplate": {"spec"},
+	"CronWorkflow":            {"spec", "workflowSpec"},
+}
+
+// argoApplicationSpecPaths is the path to the application spec for each Argo
+// CD kind.
+var argoApplicationSpecPaths = map[string][]string{
+	"Application":    {"spec"},
+	"ApplicationSet": {"spec", "template", "spec"},
+}
+
+type Argo struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (a *Argo) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the Argo refs from the documents. For Argo Workflows, it extracts
+// the container images of every template. For Argo CD, it extracts the
+// targetRevision of git sources hosted on GitHub, so they are pinned to a
+// commit SHA. Helm chart sources are ignored, since chart versions cannot be
+// pinned to a commit.
+func (a *Argo) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := a.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (a *Argo) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		apiVersion := mappingValue(docMap, "apiVersion")
+		kind := mappingValue(docMap, "kind")
+		if apiVersion == nil || kind == nil || !strings.HasPrefix(apiVersion.Value, "argoproj.io/") {
+			continue
+		}
+
+		if pth, ok := argoWorkflowSpecPaths[kind.Value]; ok {
+			a.parseWorkflowSpec(refs, lookupPath(docMap, pth))
+		}
+		if pth, ok := argoApplicationSpecPaths[kind.Value]; ok {
+			a.parseApplicationSpec(refs, lookupPath(docMap, pth))
+		}
+	}
+
+	return nil
+}
+
+// parseWorkflowSpec extracts the images from the templates of the workflow
+// spec, including the template defaults.
+func (a *Argo) parseWorkflowSpec(refs *RefsList, spec *yaml.Node) {
+	if spec == nil {
+		return
+	}
+
+	a.parseTemplate(refs, mappingValue(spec, "templateDefaults"))
+
+	templates := mappingValue(spec, "templates")
+	if templates == nil || templates.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, template := range templates.Content {
+		a.parseTemplate(refs, template)
+	}
+}
+
+// parseTemplate extracts the images from the container, script, init
+// containers, sidecars, and container set of the template.
+func (a *Argo) parseTemplate(refs *RefsList, template *yaml.Node) {
+	if template == nil || template.Kind != yaml.MappingNode {
+		return
+	}
+
+	containers := []*yaml.Node{
+		mappingValue(template, "container"),
+		mappingValue(template, "script"),
+	}
+	for _, field := range []*yaml.Node{
+		mappingValue(template, "initContainers"),
+		mappingValue(template, "sidecars"),
+		mappingValue(mappingValue(template, "containerSet"), "containers"),
+	} {
+		if field != nil && field.Kind == yaml.SequenceNode {
+			containers = append(containers, field.Content...)
+		}
+	}
+
+	for _, container := range containers {
+		image := mappingValue(container, "image")
+		if image == nil || image.Kind != yaml.ScalarNode || image.Value == "" ||
+			strings.Contains(image.Value, "{{") {
+			continue
+		}
+
+		ref := resolver.NormalizeContainerRef(image.Value)
+		refs.Add(ref, image)
+	}
+}
+
+// parseApplicationSpec extracts the targetRevision of the sources of the
+// application spec.
+func (a *Argo) parseApplicationSpec(refs *RefsList, spec *yaml.Node) {
+	if spec == nil {
+		return
+	}
+
+	sources := []*yaml.Node{mappingValue(spec, "source")}
+	if v := mappingValue(spec, "sources"); v != nil && v.Kind == yaml.SequenceNode {
+		sources = append(sources, v.Content...)
+	}
+
+	for _, source := range sources {
+		if source == nil || mappingValue(source, "chart") != nil {
+			continue
+		}
+
+		repoURL := mappingValue(source, "repoURL")
+		revision := mappingValue(source, "targetRevision")
+		if repoURL == nil || revision == nil || revision.Kind != yaml.ScalarNode {
+			continue
+		}
+
+		// Skip empty revisions (which are HEAD), version constraints (e.g.
+		// "1.*"), and ApplicationSet templates (e.g. "{{.branch}}").
+		if revision.Value == "" || strings.ContainsAny(revision.Value, "*<>=~^ {}") {
+			continue
+		}
+
+		name := githubRepo(repoURL.Value)
+		if name == "" {
+			continue
+		}
+
+		projection := versionProjection(name, "")
+		refs.AddProjected(resolver.NormalizeActionsRef(projection.Ref(revision.Value)), revision, projection)
+	}
+}
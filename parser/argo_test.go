@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+func TestArgo_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: Workflow
+`,
+			exp: nil,
+		},
+		{
+			name: "workflow_template",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: WorkflowTemplate
+metadata:
+  name: build
+spec:
+  templateDefaults:
+    container:
+      image: busybox:1.36
+  templates:
+    - name: main
+      container:
+        image: golang:1.24
+      initContainers:
+        - name: init
+          image: alpine:3.20
+      sidecars:
+        - name: cache
+          image: redis:7
+    - name: lint
+      script:
+        image: python:3.12
+    - name: set
+      containerSet:
+        containers:
+          - name: a
+            image: node:22
+    - name: param
+      container:
+        image: '{{inputs.parameters.image}}'
+    - name: steps
+      steps:
+        - - name: build
+            template: main
+`,
+			exp: []string{
+				"container://alpine:3.20",
+				"container://busybox:1.36",
+				"container://golang:1.24",
+				"container://node:22",
+				"container://python:3.12",
+				"container://redis:7",
+			},
+		},
+		{
+			name: "cron_workflow",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: CronWorkflow
+metadata:
+  name: nightly
+spec:
+  schedule: "0 0 * * *"
+  workflowSpec:
+    templates:
+      - name: main
+        container:
+          image: ubuntu:24.04
+`,
+			exp: []string{
+				"container://ubuntu:24.04",
+			},
+		},
+		{
+			name: "application",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+spec:
+  source:
+    repoURL: https://github.com/argoproj/argocd-example-apps.git
+    targetRevision: HEAD
+    path: guestbook
+`,
+			exp: []string{
+				"actions://argoproj/argocd-example-apps@HEAD",
+			},
+		},
+		{
+			name: "application_sources",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+spec:
+  sources:
+    - repoURL: git@github.com:my-org/config
+      targetRevision: v1.2.0
+      ref: values
+    - repoURL: https://charts.example.com
+      chart: guestbook
+      targetRevision: 1.0.0
+    - repoURL: https://gitlab.com/my-org/config.git
+      targetRevision: main
+    - repoURL: https://github.com/my-org/manifests
+      targetRevision: 1.*
+    - repoURL: https://github.com/my-org/default-branch
+`,
+			exp: []string{
+				"actions://my-org/config@v1.2.0",
+			},
+		},
+		{
+			name: "application_set",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: ApplicationSet
+metadata:
+  name: guestbook
+spec:
+  template:
+    spec:
+      source:
+        repoURL: https://github.com/argoproj/argocd-example-apps.git
+        targetRevision: main
+      sources:
+        - repoURL: https://github.com/argoproj/argocd-example-apps.git
+          targetRevision: '{{.branch}}'
+`,
+			exp: []string{
+				"actions://argoproj/argocd-example-apps@main",
+			},
+		},
+		{
+			name: "other_kinds",
+			in: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  templates:
+    - container:
+        image: ubuntu:24.04
+`,
+			exp: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Argo).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestArgo_Pin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://argoproj/argocd-example-apps@HEAD": {
+			Resolved: "argoproj/argocd-example-apps@c4a0b883114b00d8d76b479c820ce7950211c99b",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+spec:
+  source:
+    repoURL: https://github.com/argoproj/argocd-example-apps.git
+    targetRevision: HEAD
+    path: guestbook
+`
+	exp := `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+spec:
+  source:
+    repoURL: https://github.com/argoproj/argocd-example-apps.git
+    targetRevision: c4a0b883114b00d8d76b479c820ce7950211c99b # ratchet:HEAD
+    path: guestbook
+`
+
+	m := helperStringToYAML(t, in)
+
+	if err := Pin(ctx, res, new(Argo), map[string]*yaml.Node{"test.yml": m}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(exp); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+
+	if err := Unpin(ctx, new(Argo), map[string]*yaml.Node{"test.yml": m}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(in); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/internal/dockerfile"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+// autoFallback is the parser used when no other parser can be detected. This
+// preserves the historical default behavior.
+const autoFallback = "actions"
+
+// Auto is a parser that detects the parser for each file based on its path and
+// the shape of its contents. This allows operating on a mixed set of files in a
+// single run.
+type Auto struct {
+	options *Options
+}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (a *Auto) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse detects the parser for each file and merges the refs from all files.
+func (a *Auto) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		name := detectParser(pth, node)
+		fn, ok := parserFactory[name]
+		if !ok {
+			return nil, fmt.Errorf("failed to parse %s: unknown parser %q", pth, name)
+		}
+
+		par := fn()
+		a.options.apply(par)
+
+		fileRefs, err := par.Parse(map[string]*yaml.Node{
+			pth: node,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		refs.Merge(fileRefs)
+	}
+
+	return &refs, nil
+}
+
+// detectParser returns the name of the parser for the file at the given path.
+// Well-known paths take precedence over the shape of the document.
+func detectParser(pth string, node *yaml.Node) string {
+	pth = strings.ToLower(path.Clean(strings.ReplaceAll(pth, "\\", "/")))
+	base := path.Base(pth)
+	ext := path.Ext(base)
+
+	if dockerfile.IsDockerfile(base) {
+		return "dockerfile"
+	}
+
+	if ext == ".yml" || ext == ".yaml" {
+		name := strings.TrimSuffix(base, ext)
+
+		switch {
+		case strings.HasPrefix(pth, ".github/workflows/"),
+			strings.Contains(pth, "/.github/workflows/"),
+			name == "action":
+			return "actions"
+		case strings.HasPrefix(pth, ".gitea/workflows/"),
+			strings.Contains(pth, "/.gitea/workflows/"),
+			strings.HasPrefix(pth, ".forgejo/workflows/"),
+			strings.Contains(pth, "/.forgejo/workflows/"):
+			return "gitea"
+		case name == ".woodpecker",
+			path.Base(path.Dir(pth)) == ".woodpecker":
+			return "woodpecker"
+		case name == ".gitlab-ci":
+			return "gitlabci"
+		case name == "config" && path.Base(path.Dir(pth)) == ".circleci":
+			return "circleci"
+		case name == ".drone":
+			return "drone"
+		case name == "cloudbuild":
+			return "cloudbuild"
+		case strings.HasPrefix(name, "azure-pipelines"),
+			path.Base(path.Dir(pth)) == ".azure-pipelines":
+			return "azurepipelines"
+		case name == "bitbucket-pipelines":
+			return "bitbucket"
+		case path.Base(path.Dir(pth)) == ".buildkite":
+			return "buildkite"
+		case name == ".pre-commit-config":
+			return "precommit"
+		case name == "compose", name == "docker-compose",
+			strings.HasPrefix(name, "compose."), strings.HasPrefix(name, "docker-compose."):
+			return "compose"
+		case name == "values", strings.HasPrefix(name, "values-"), strings.HasPrefix(name, "values."):
+			// Many tools name files "values", so only treat the file as Helm
+			// values if it declares a Helm-style image.
+			if node != nil && hasHelmImage(node) {
+				return "helm"
+			}
+		}
+	}
+
+	if node == nil {
+		return autoFallback
+	}
+
+	if dockerfile.Instructions(node) != nil {
+		return "dockerfile"
+	}
+
+	for _, docMap := range node.Content {
+		if docMap.Kind != yaml.MappingNode {
+			continue
+		}
+
+		keys := make(map[string]*yaml.Node, len(docMap.Content)/2)
+		for i := 0; i+1 < len(docMap.Content); i += 2 {
+			keys[docMap.Content[i].Value] = docMap.Content[i+1]
+		}
+
+		if v, ok := keys["apiVersion"]; ok && strings.HasPrefix(v.Value, "tekton.dev/") {
+			return "tekton"
+		}
+
+		if v, ok := keys["apiVersion"]; ok && strings.HasPrefix(v.Value, "argoproj.io/") {
+			return "argo"
+		}
+
+		if _, ok := keys["apiVersion"]; ok {
+			if v, ok := keys["kind"]; ok {
+				if _, ok := kubernetesPodSpecPaths[v.Value]; ok || v.Value == "List" {
+					return "kubernetes"
+				}
+			}
+		}
+
+		if v, ok := keys["kind"]; ok && v.Value == "pipeline" {
+			return "drone"
+		}
+
+		_, hasOn := keys["on"]
+		_, hasJobs := keys["jobs"]
+		_, hasRuns := keys["runs"]
+		if (hasOn && hasJobs) || hasRuns {
+			return "actions"
+		}
+
+		_, hasVersion := keys["version"]
+		_, hasOrbs := keys["orbs"]
+		_, hasWorkflows := keys["workflows"]
+		_, hasExecutors := keys["executors"]
+		if hasVersion && (hasOrbs || hasWorkflows || hasExecutors || hasJobs) {
+			return "circleci"
+		}
+
+		if v, ok := keys["pipelines"]; ok && v.Kind == yaml.MappingNode {
+			return "bitbucket"
+		}
+
+		_, hasTrigger := keys["trigger"]
+		_, hasPool := keys["pool"]
+		_, hasStages := keys["stages"]
+		_, hasSteps := keys["steps"]
+		if (hasTrigger || hasPool) && (hasStages || hasJobs || hasSteps) {
+			return "azurepipelines"
+		}
+
+		if v, ok := keys["steps"]; ok && isCloudBuildSteps(v) {
+			return "cloudbuild"
+		}
+
+		if v, ok := keys["repos"]; ok && v.Kind == yaml.SequenceNode &&
+			len(v.Content) > 0 && mappingValue(v.Content[0], "repo") != nil {
+			return "precommit"
+		}
+
+		if v, ok := keys["services"]; ok && v.Kind == yaml.MappingNode && !hasJobs {
+			return "compose"
+		}
+	}
+
+	return autoFallback
+}
+
+// hasHelmImage returns true if the node contains a mapping with the
+// "repository" key and a "tag" or "digest" key, which is how Helm charts
+// conventionally declare images.
+func hasHelmImage(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if hasHelmImage(child) {
+				return true
+			}
+		}
+	case yaml.MappingNode:
+		if mappingValue(node, "repository") != nil &&
+			(mappingValue(node, "tag") != nil || mappingValue(node, "digest") != nil) {
+			return true
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			if hasHelmImage(node.Content[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCloudBuildSteps returns true if every step in the sequence names its
+// builder image with "name". Steps with an "image" (Drone, Woodpecker) or
+// "uses" or "run" (GitHub Actions-like systems) are not Cloud Build steps.
+func isCloudBuildSteps(node *yaml.Node) bool {
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return false
+	}
+
+	for _, step := range node.Content {
+		if step.Kind != yaml.MappingNode {
+			return false
+		}
+
+		if name := mappingValue(step, "name"); name == nil || name.Kind != yaml.ScalarNode {
+			return false
+		}
+
+		for _, key := range []string{"image", "uses", "run"} {
+			if mappingValue(step, key) != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,381 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestAuto_Parse(t *testing.T) {
+	t.Parallel()
+
+	nodes := map[string]*yaml.Node{
+		".github/workflows/test.yml": helperStringToYAML(t, `
+jobs:
+  my_job:
+    steps:
+      - uses: 'actions/checkout@v3'
+`),
+		".gitlab-ci.yml": helperStringToYAML(t, `
+build:
+  image: node:12
+`),
+		"ci/tekton.yml": helperStringToYAML(t, `
+apiVersion: tekton.dev/v1beta1
+kind: Task
+spec:
+  steps:
+    - name: git
+      image: alpine/git
+`),
+	}
+
+	refs, err := new(Auto).Parse(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{
+		"actions://actions/checkout@v3",
+		"container://alpine/git",
+		"container://node:12",
+	}
+	if got, want := refs.Refs(), exp; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q to be %q", got, want)
+	}
+}
+
+func TestDetectParser(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		pth  string
+		in   string
+		exp  string
+	}{
+		{
+			name: "github_workflow",
+			pth:  ".github/workflows/test.yml",
+			in:   `foo: bar`,
+			exp:  "actions",
+		},
+		{
+			name: "github_workflow_nested",
+			pth:  "sub/.github/workflows/test.yaml",
+			in:   `foo: bar`,
+			exp:  "actions",
+		},
+		{
+			name: "github_action",
+			pth:  "path/to/action.yml",
+			in:   `foo: bar`,
+			exp:  "actions",
+		},
+		{
+			name: "gitlabci",
+			pth:  ".gitlab-ci.yml",
+			in:   `foo: bar`,
+			exp:  "gitlabci",
+		},
+		{
+			name: "circleci",
+			pth:  ".circleci/config.yml",
+			in:   `foo: bar`,
+			exp:  "circleci",
+		},
+		{
+			name: "drone",
+			pth:  ".drone.yml",
+			in:   `foo: bar`,
+			exp:  "drone",
+		},
+		{
+			name: "cloudbuild",
+			pth:  "cloudbuild.yaml",
+			in:   `foo: bar`,
+			exp:  "cloudbuild",
+		},
+		{
+			name: "azurepipelines",
+			pth:  "azure-pipelines.yml",
+			in:   `foo: bar`,
+			exp:  "azurepipelines",
+		},
+		{
+			name: "azurepipelines_directory",
+			pth:  ".azure-pipelines/build.yml",
+			in:   `foo: bar`,
+			exp:  "azurepipelines",
+		},
+		{
+			name: "bitbucket",
+			pth:  "bitbucket-pipelines.yml",
+			in:   `foo: bar`,
+			exp:  "bitbucket",
+		},
+		{
+			name: "buildkite",
+			pth:  ".buildkite/pipeline.yml",
+			in:   `foo: bar`,
+			exp:  "buildkite",
+		},
+		{
+			name: "gitea",
+			pth:  ".gitea/workflows/test.yml",
+			in:   `foo: bar`,
+			exp:  "gitea",
+		},
+		{
+			name: "forgejo",
+			pth:  "sub/.forgejo/workflows/test.yaml",
+			in:   `foo: bar`,
+			exp:  "gitea",
+		},
+		{
+			name: "woodpecker",
+			pth:  ".woodpecker.yml",
+			in:   `foo: bar`,
+			exp:  "woodpecker",
+		},
+		{
+			name: "woodpecker_directory",
+			pth:  ".woodpecker/build.yaml",
+			in:   `foo: bar`,
+			exp:  "woodpecker",
+		},
+		{
+			name: "compose",
+			pth:  "compose.yaml",
+			in:   `foo: bar`,
+			exp:  "compose",
+		},
+		{
+			name: "docker_compose_override",
+			pth:  "deploy/docker-compose.override.yml",
+			in:   `foo: bar`,
+			exp:  "compose",
+		},
+		{
+			name: "helm_values",
+			pth:  "charts/app/values.yaml",
+			in: `
+image:
+  repository: nginx
+  tag: "1.25"
+`,
+			exp: "helm",
+		},
+		{
+			name: "helm_values_env",
+			pth:  "charts/app/values-prod.yaml",
+			in: `
+app:
+  sidecars:
+    - image:
+        repository: envoyproxy/envoy
+        digest: ""
+`,
+			exp: "helm",
+		},
+		{
+			name: "values_without_helm_image",
+			pth:  "config/values.yml",
+			in: `
+image: nginx:1.25
+replicas: 1
+`,
+			exp: "actions",
+		},
+		{
+			name: "values_with_other_shape",
+			pth:  "ci/values-build.yml",
+			in: `
+steps:
+  - name: gcr.io/cloud-builders/docker
+    args: ['build', '.']
+`,
+			exp: "cloudbuild",
+		},
+		{
+			name: "dockerfile",
+			pth:  "Dockerfile",
+			in:   `foo: bar`,
+			exp:  "dockerfile",
+		},
+		{
+			name: "dockerfile_suffix",
+			pth:  "build/app.Dockerfile",
+			in:   `foo: bar`,
+			exp:  "dockerfile",
+		},
+		{
+			name: "precommit",
+			pth:  ".pre-commit-config.yaml",
+			in:   `foo: bar`,
+			exp:  "precommit",
+		},
+		{
+			name: "precommit_shape",
+			pth:  "hooks.yml",
+			in: `
+repos:
+  - repo: https://github.com/psf/black
+    rev: 23.12.1
+`,
+			exp: "precommit",
+		},
+		{
+			name: "tekton_shape",
+			pth:  "task.yml",
+			in: `
+apiVersion: tekton.dev/v1
+kind: Task
+`,
+			exp: "tekton",
+		},
+		{
+			name: "argo_shape",
+			pth:  "workflow.yml",
+			in: `
+apiVersion: argoproj.io/v1alpha1
+kind: WorkflowTemplate
+`,
+			exp: "argo",
+		},
+		{
+			name: "kubernetes_shape",
+			pth:  "deploy.yml",
+			in: `
+apiVersion: apps/v1
+kind: Deployment
+`,
+			exp: "kubernetes",
+		},
+		{
+			name: "drone_shape",
+			pth:  "ci.yml",
+			in: `
+kind: pipeline
+steps: []
+`,
+			exp: "drone",
+		},
+		{
+			name: "actions_shape",
+			pth:  "workflow.yml",
+			in: `
+on: push
+jobs: {}
+`,
+			exp: "actions",
+		},
+		{
+			name: "circleci_shape",
+			pth:  "ci.yml",
+			in: `
+version: 2.1
+workflows: {}
+`,
+			exp: "circleci",
+		},
+		{
+			name: "cloudbuild_shape",
+			pth:  "build.yml",
+			in: `
+steps:
+  - name: ubuntu
+  - name: gcr.io/cloud-builders/docker
+    args: ['build', '.']
+`,
+			exp: "cloudbuild",
+		},
+		{
+			name: "steps_with_images",
+			pth:  "ci.yml",
+			in: `
+steps:
+  - name: build
+    image: golang:1.24
+    commands:
+      - go build
+`,
+			exp: "actions",
+		},
+		{
+			name: "steps_with_uses",
+			pth:  "ci.yml",
+			in: `
+steps:
+  - name: checkout
+    uses: actions/checkout@v4
+`,
+			exp: "actions",
+		},
+		{
+			name: "steps_without_names",
+			pth:  "ci.yml",
+			in: `
+steps:
+  - script: echo hello
+`,
+			exp: "actions",
+		},
+		{
+			name: "steps_empty",
+			pth:  "ci.yml",
+			in:   `steps: []`,
+			exp:  "actions",
+		},
+		{
+			name: "azurepipelines_shape",
+			pth:  "ci.yml",
+			in: `
+trigger:
+  - main
+pool:
+  vmImage: ubuntu-latest
+steps:
+  - script: echo hello
+`,
+			exp: "azurepipelines",
+		},
+		{
+			name: "bitbucket_shape",
+			pth:  "ci.yml",
+			in: `
+image: node:18
+pipelines:
+  default: []
+`,
+			exp: "bitbucket",
+		},
+		{
+			name: "compose_shape",
+			pth:  "dev.yml",
+			in: `
+services:
+  db:
+    image: postgres:16
+`,
+			exp: "compose",
+		},
+		{
+			name: "fallback",
+			pth:  "unknown.yml",
+			in:   `foo: bar`,
+			exp:  "actions",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := detectParser(tc.pth, helperStringToYAML(t, tc.in)), tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type AzurePipelines struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (a *AzurePipelines) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the Azure Pipelines refs from the documents. It extracts
+// container images from jobs and container resources, and the refs of GitHub
+// repository resources. It does not support references with variables or
+// template expressions.
+func (a *AzurePipelines) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := a.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (a *AzurePipelines) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		if docMap.Kind != yaml.MappingNode {
+			continue
+		}
+
+		// Jobs can reference container resources by their alias, which are not
+		// images themselves.
+		aliases := make(map[string]struct{}, 4)
+
+		if resources := mappingValue(docMap, "resources"); resources != nil {
+			if containers := mappingValue(resources, "containers"); containers != nil &&
+				containers.Kind == yaml.SequenceNode {
+				for _, container := range containers.Content {
+					if alias := mappingValue(container, "container"); alias != nil {
+						aliases[alias.Value] = struct{}{}
+					}
+					if image := mappingValue(container, "image"); image != nil && image.Kind == yaml.ScalarNode {
+						ref := resolver.NormalizeContainerRef(image.Value)
+						refs.Add(ref, image)
+					}
+				}
+			}
+
+			if repositories := mappingValue(resources, "repositories"); repositories != nil &&
+				repositories.Kind == yaml.SequenceNode {
+				for _, repository := range repositories.Content {
+					a.parseRepository(refs, repository)
+				}
+			}
+		}
+
+		// Single-job pipelines may declare the container at the top level.
+		a.parseJob(refs, aliases, docMap)
+
+		if jobs := mappingValue(docMap, "jobs"); jobs != nil && jobs.Kind == yaml.SequenceNode {
+			for _, job := range jobs.Content {
+				a.parseJob(refs, aliases, job)
+			}
+		}
+
+		if stages := mappingValue(docMap, "stages"); stages != nil && stages.Kind == yaml.SequenceNode {
+			for _, stage := range stages.Content {
+				jobs := mappingValue(stage, "jobs")
+				if jobs == nil || jobs.Kind != yaml.SequenceNode {
+					continue
+				}
+				for _, job := range jobs.Content {
+					a.parseJob(refs, aliases, job)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseJob extracts the container image from the job, if any.
+func (a *AzurePipelines) parseJob(refs *RefsList, aliases map[string]struct{}, job *yaml.Node) {
+	container := mappingValue(job, "container")
+	if container == nil {
+		return
+	}
+
+	switch container.Kind {
+	case yaml.ScalarNode:
+		if _, ok := aliases[container.Value]; ok {
+			return
+		}
+		ref := resolver.NormalizeContainerRef(container.Value)
+		refs.Add(ref, container)
+	case yaml.MappingNode:
+		if image := mappingValue(container, "image"); image != nil && image.Kind == yaml.ScalarNode {
+			ref := resolver.NormalizeContainerRef(image.Value)
+			refs.Add(ref, image)
+		}
+	}
+}
+
+// parseRepository extracts the ref of a GitHub repository resource. Branch and
+// tag refs may be fully-qualified (e.g. "refs/tags/v1"), and the prefix is kept
+// when the ref is upgraded.
+func (a *AzurePipelines) parseRepository(refs *RefsList, repository *yaml.Node) {
+	typ := mappingValue(repository, "type")
+	if typ == nil || !strings.EqualFold(typ.Value, "github") {
+		return
+	}
+
+	name := mappingValue(repository, "name")
+	ref := mappingValue(repository, "ref")
+	if name == nil || ref == nil || name.Value == "" || ref.Value == "" {
+		return
+	}
+
+	var prefix string
+	for _, p := range []string{"refs/heads/", "refs/tags/"} {
+		if strings.HasPrefix(ref.Value, p) {
+			prefix = p
+			break
+		}
+	}
+
+	projection := versionProjection(name.Value, prefix)
+	refs.AddProjected(resolver.NormalizeActionsRef(projection.Ref(ref.Value)), ref, projection)
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestAzurePipelines_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+jobs:
+`,
+			exp: nil,
+		},
+		{
+			name: "top_level_container",
+			in: `
+pool:
+  vmImage: ubuntu-latest
+container: ubuntu:20.04
+steps:
+  - script: echo hello
+`,
+			exp: []string{
+				"container://ubuntu:20.04",
+			},
+		},
+		{
+			name: "jobs",
+			in: `
+jobs:
+  - job: build
+    container: ubuntu:20.04
+  - job: test
+    container:
+      image: golang:1.12
+  - job: lint
+    steps:
+      - task: Docker@2
+`,
+			exp: []string{
+				"container://golang:1.12",
+				"container://ubuntu:20.04",
+			},
+		},
+		{
+			name: "stages",
+			in: `
+stages:
+  - stage: build
+    jobs:
+      - job: build
+        container:
+          image: golang:1.12
+      - deployment: deploy
+        container: ubuntu:20.04
+`,
+			exp: []string{
+				"container://golang:1.12",
+				"container://ubuntu:20.04",
+			},
+		},
+		{
+			name: "container_resources",
+			in: `
+resources:
+  containers:
+    - container: linux
+      image: ubuntu:20.04
+jobs:
+  - job: build
+    container: linux
+`,
+			exp: []string{
+				"container://ubuntu:20.04",
+			},
+		},
+		{
+			name: "repository_resources",
+			in: `
+resources:
+  repositories:
+    - repository: tagged
+      type: github
+      name: actions/checkout
+      ref: refs/tags/v4
+    - repository: branch
+      type: github
+      name: actions/setup-go
+      ref: main
+    - repository: no_ref
+      type: github
+      name: actions/cache
+    - repository: azure
+      type: git
+      name: project/repo
+      ref: refs/heads/main
+`,
+			exp: []string{
+				"actions://actions/checkout@v4",
+				"actions://actions/setup-go@main",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(AzurePipelines).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"fmt"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type Bitbucket struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (b *Bitbucket) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the Bitbucket Pipelines refs from the documents. It extracts the
+// global image, service images, and step images from every pipeline section.
+// It does not support references with variables.
+func (b *Bitbucket) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := b.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (b *Bitbucket) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		if docMap.Kind != yaml.MappingNode {
+			continue
+		}
+
+		// image: keyword
+		b.parseImage(refs, mappingValue(docMap, "image"))
+
+		// definitions: keyword
+		if definitions := mappingValue(docMap, "definitions"); definitions != nil {
+			if services := mappingValue(definitions, "services"); services != nil &&
+				services.Kind == yaml.MappingNode {
+				for i := 1; i < len(services.Content); i += 2 {
+					b.parseImage(refs, mappingValue(services.Content[i], "image"))
+				}
+			}
+
+			b.parseSteps(refs, mappingValue(definitions, "steps"))
+		}
+
+		// pipelines: keyword
+		pipelines := mappingValue(docMap, "pipelines")
+		if pipelines == nil || pipelines.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 0; i+1 < len(pipelines.Content); i += 2 {
+			section := pipelines.Content[i+1]
+
+			switch pipelines.Content[i].Value {
+			case "default":
+				b.parseSteps(refs, section)
+			case "branches", "pull-requests", "tags", "custom", "bookmarks":
+				if section.Kind != yaml.MappingNode {
+					continue
+				}
+				for j := 1; j < len(section.Content); j += 2 {
+					b.parseSteps(refs, section.Content[j])
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseSteps extracts the images from a list of pipeline items, which may be
+// steps, parallel groups, or stages.
+func (b *Bitbucket) parseSteps(refs *RefsList, items *yaml.Node) {
+	if items == nil || items.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for _, item := range items.Content {
+		if step := mappingValue(item, "step"); step != nil {
+			b.parseImage(refs, mappingValue(step, "image"))
+		}
+
+		// parallel: is either a list of steps, or a map with a "steps" key.
+		if parallel := mappingValue(item, "parallel"); parallel != nil {
+			if steps := mappingValue(parallel, "steps"); steps != nil {
+				parallel = steps
+			}
+			b.parseSteps(refs, parallel)
+		}
+
+		if stage := mappingValue(item, "stage"); stage != nil {
+			b.parseSteps(refs, mappingValue(stage, "steps"))
+		}
+	}
+}
+
+// parseImage registers the image, which is either a string or a map with a
+// "name" key.
+func (b *Bitbucket) parseImage(refs *RefsList, image *yaml.Node) {
+	if image == nil {
+		return
+	}
+
+	if image.Kind == yaml.MappingNode {
+		image = mappingValue(image, "name")
+		if image == nil {
+			return
+		}
+	}
+
+	if image.Kind != yaml.ScalarNode || image.Value == "" {
+		return
+	}
+
+	ref := resolver.NormalizeContainerRef(image.Value)
+	refs.Add(ref, image)
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestBitbucket_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+pipelines:
+`,
+			exp: nil,
+		},
+		{
+			name: "global_image",
+			in: `
+image:
+  name: atlassian/default-image:3
+  username: $USERNAME
+`,
+			exp: []string{
+				"container://atlassian/default-image:3",
+			},
+		},
+		{
+			name: "services",
+			in: `
+definitions:
+  services:
+    docker:
+      memory: 2048
+    postgres:
+      image: postgres:16
+    redis:
+      image:
+        name: redis:7
+`,
+			exp: []string{
+				"container://postgres:16",
+				"container://redis:7",
+			},
+		},
+		{
+			name: "pipelines",
+			in: `
+pipelines:
+  default:
+    - step:
+        image: node:18
+    - parallel:
+        - step:
+            image: golang:1.12
+  branches:
+    main:
+      - stage:
+          steps:
+            - step:
+                image: ubuntu:20.04
+  pull-requests:
+    '**':
+      - parallel:
+          fail-fast: true
+          steps:
+            - step:
+                image: alpine:3
+  tags:
+    'v*':
+      - step:
+          image: python:3
+  custom:
+    release:
+      - variables:
+          - name: VERSION
+      - step:
+          image:
+            name: ruby:3
+`,
+			exp: []string{
+				"container://alpine:3",
+				"container://golang:1.12",
+				"container://node:18",
+				"container://python:3",
+				"container://ruby:3",
+				"container://ubuntu:20.04",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Bitbucket).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
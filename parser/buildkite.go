@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type Buildkite struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (b *Buildkite) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the Buildkite refs from the documents. It extracts plugins that
+// are hosted on GitHub (e.g. "docker#v5.9.0") as actions-style refs, so they
+// are pinned to commit SHAs, and container images from steps and the docker
+// plugin. It does not support references with variables.
+func (b *Buildkite) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := b.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (b *Buildkite) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		if docMap.Kind != yaml.MappingNode {
+			continue
+		}
+
+		b.parseSteps(refs, mappingValue(docMap, "steps"))
+	}
+
+	return nil
+}
+
+// parseSteps extracts the refs from the list of steps, including the steps
+// nested in groups.
+func (b *Buildkite) parseSteps(refs *RefsList, steps *yaml.Node) {
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for _, step := range steps.Content {
+		if step.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if image := mappingValue(step, "image"); image != nil && image.Kind == yaml.ScalarNode {
+			ref := resolver.NormalizeContainerRef(image.Value)
+			refs.Add(ref, image)
+		}
+
+		// plugins: is either a list of plugins, or a map of plugin to config.
+		plugins := mappingValue(step, "plugins")
+		switch {
+		case plugins == nil:
+		case plugins.Kind == yaml.SequenceNode:
+			for _, plugin := range plugins.Content {
+				switch plugin.Kind {
+				case yaml.ScalarNode:
+					b.parsePlugin(refs, plugin, nil)
+				case yaml.MappingNode:
+					for i := 0; i+1 < len(plugin.Content); i += 2 {
+						b.parsePlugin(refs, plugin.Content[i], plugin.Content[i+1])
+					}
+				}
+			}
+		case plugins.Kind == yaml.MappingNode:
+			for i := 0; i+1 < len(plugins.Content); i += 2 {
+				b.parsePlugin(refs, plugins.Content[i], plugins.Content[i+1])
+			}
+		}
+
+		b.parseSteps(refs, mappingValue(step, "steps"))
+	}
+}
+
+// parsePlugin registers the plugin with the given name (e.g. "docker#v5.9.0")
+// and any container image in its config.
+func (b *Buildkite) parsePlugin(refs *RefsList, plugin, config *yaml.Node) {
+	source, version, _ := strings.Cut(plugin.Value, "#")
+	repo := buildkitePluginRepo(source)
+	if repo == "" {
+		return
+	}
+
+	if version != "" {
+		projection := &Projection{
+			Value: func(ref string) string {
+				_, version, _ := strings.Cut(ref, "@")
+				return source + "#" + version
+			},
+			Ref: func(value string) string {
+				_, version, _ := strings.Cut(value, "#")
+				return repo + "@" + version
+			},
+		}
+		refs.AddProjected(resolver.NormalizeActionsRef(projection.Ref(plugin.Value)), plugin, projection)
+	}
+
+	if repo == "buildkite-plugins/docker-buildkite-plugin" {
+		if image := mappingValue(config, "image"); image != nil && image.Kind == yaml.ScalarNode {
+			ref := resolver.NormalizeContainerRef(image.Value)
+			refs.Add(ref, image)
+		}
+	}
+}
+
+// buildkitePluginRepo returns the GitHub repository ("owner/repo") for the
+// plugin source, following the Buildkite naming conventions:
+//
+//	docker                            -> buildkite-plugins/docker-buildkite-plugin
+//	my-org/my-plugin                  -> my-org/my-plugin-buildkite-plugin
+//	https://github.com/my-org/my-repo -> my-org/my-repo
+//
+// It returns the empty string for plugins that are not hosted on GitHub.
+func buildkitePluginRepo(source string) string {
+	if repo := githubRepo(source); repo != "" {
+		return repo
+	}
+
+	// Any other host is not supported.
+	if strings.ContainsAny(source, ".:") {
+		return ""
+	}
+
+	owner, name := "buildkite-plugins", source
+	if before, after, ok := strings.Cut(source, "/"); ok {
+		owner, name = before, after
+	}
+	if owner == "" || name == "" || strings.Contains(name, "/") {
+		return ""
+	}
+
+	if !strings.HasSuffix(name, "-buildkite-plugin") {
+		name += "-buildkite-plugin"
+	}
+	return owner + "/" + name
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestBuildkite_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+steps:
+`,
+			exp: nil,
+		},
+		{
+			name: "plugins_list",
+			in: `
+steps:
+  - command: make
+    plugins:
+      - docker#v5.9.0:
+          image: golang:1.12
+      - docker-compose#v4.16.0
+      - my-org/my-plugin#v1.0.0: ~
+      - https://github.com/my-org/other-repo.git#v2.0.0: ~
+      - ssh://git@gitlab.com/my-org/plugin.git#v3.0.0: ~
+      - unversioned: ~
+`,
+			exp: []string{
+				"actions://buildkite-plugins/docker-buildkite-plugin@v5.9.0",
+				"actions://buildkite-plugins/docker-compose-buildkite-plugin@v4.16.0",
+				"actions://my-org/my-plugin-buildkite-plugin@v1.0.0",
+				"actions://my-org/other-repo@v2.0.0",
+				"container://golang:1.12",
+			},
+		},
+		{
+			name: "plugins_map",
+			in: `
+steps:
+  - command: make
+    plugins:
+      docker#v5.9.0:
+        image: golang:1.12
+`,
+			exp: []string{
+				"actions://buildkite-plugins/docker-buildkite-plugin@v5.9.0",
+				"container://golang:1.12",
+			},
+		},
+		{
+			name: "groups",
+			in: `
+steps:
+  - wait
+  - group: tests
+    steps:
+      - command: make test
+        image: ubuntu:20.04
+        plugins:
+          - docker#v5.9.0: ~
+`,
+			exp: []string{
+				"actions://buildkite-plugins/docker-buildkite-plugin@v5.9.0",
+				"container://ubuntu:20.04",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Buildkite).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
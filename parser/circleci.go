@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	// Using banydonk/yaml instead of the default yaml pkg because the default
 	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
@@ -16,9 +17,10 @@ func (c *CircleCI) DenormalizeRef(ref string) string {
 	return resolver.DenormalizeRef(ref)
 }
 
-// Parse pulls the CircleCI refs from the documents. Unfortunately it does not
-// process "orbs" because there is no documented API for resolving orbs to an
-// absolute version.
+// Parse pulls the CircleCI refs from the documents. Container images are
+// resolved to digests, and "orbs" are resolved to the exact published version
+// (e.g. "circleci/node@5.1" is "circleci/node@5.1.0"). Inline orbs, dev
+// versions, and versions with pipeline parameters are ignored.
 func (c *CircleCI) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
 	var refs RefsList
 
@@ -46,6 +48,8 @@ func (c *CircleCI) parseOne(refs *RefsList, node *yaml.Node) error {
 			continue
 		}
 
+		c.parseOrbs(refs, mappingValue(docMap, "orbs"))
+
 		// jobs: and executors: keyword
 		for i, jobsMap := range docMap.Content {
 			if jobsMap.Value != "jobs" && jobsMap.Value != "executors" {
@@ -90,3 +94,26 @@ func (c *CircleCI) parseOne(refs *RefsList, node *yaml.Node) error {
 
 	return nil
 }
+
+// parseOrbs adds the orbs in the "orbs" mapping.
+func (c *CircleCI) parseOrbs(refs *RefsList, orbs *yaml.Node) {
+	if orbs == nil || orbs.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 1; i < len(orbs.Content); i += 2 {
+		orb := orbs.Content[i]
+		if orb.Kind != yaml.ScalarNode {
+			continue
+		}
+
+		_, version, ok := strings.Cut(orb.Value, "@")
+		if !ok || version == "" ||
+			strings.HasPrefix(version, "dev:") ||
+			strings.Contains(orb.Value, "<<") {
+			continue
+		}
+
+		refs.Add(resolver.NormalizeOrbRef(orb.Value), orb)
+	}
+}
@@ -1,10 +1,13 @@
 package parser
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 func TestCircleCI_Parse(t *testing.T) {
@@ -48,6 +51,27 @@ jobs:
 				"container://ubuntu:22.04",
 			},
 		},
+		{
+			name: "orbs",
+			in: `
+orbs:
+  node: circleci/node@5.1
+  slack: circleci/slack@4.12.5
+  latest: circleci/go@volatile
+  dev: my-org/my-orb@dev:alpha
+  param: circleci/aws-cli@<< pipeline.parameters.aws-cli >>
+  inline:
+    jobs:
+      hello:
+        docker:
+          - image: ubuntu:24.04
+`,
+			exp: []string{
+				"orb://circleci/go@volatile",
+				"orb://circleci/node@5.1",
+				"orb://circleci/slack@4.12.5",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -69,3 +93,47 @@ jobs:
 		})
 	}
 }
+
+func TestCircleCI_Pin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"orb://circleci/node@5.1": {
+			Resolved: "circleci/node@5.1.0",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := `
+orbs:
+  node: circleci/node@5.1 # build tools
+  slack: circleci/slack@4.12.5
+`
+	exp := `
+orbs:
+  node: circleci/node@5.1.0 # build tools ratchet:circleci/node@5.1
+  slack: circleci/slack@4.12.5
+`
+
+	m := helperStringToYAML(t, in)
+
+	if err := Pin(ctx, res, new(CircleCI), map[string]*yaml.Node{"test.yml": m}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(exp); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+
+	if err := Unpin(ctx, new(CircleCI), map[string]*yaml.Node{"test.yml": m}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(in); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+}
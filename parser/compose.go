@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"fmt"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type Compose struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (c *Compose) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the image references from Docker Compose files. Services that
+// are built locally are skipped, since their image is the name of the built
+// image. Images with variable interpolation (e.g. "${TAG:-latest}") are also
+// skipped, since pinning them would discard the variable.
+func (c *Compose) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := c.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (c *Compose) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		if docMap.Kind != yaml.MappingNode {
+			continue
+		}
+
+		services := mappingValue(docMap, "services")
+		if services == nil || services.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 1; i < len(services.Content); i += 2 {
+			service := services.Content[i]
+			if mappingValue(service, "build") != nil {
+				continue
+			}
+
+			image := mappingValue(service, "image")
+			if image == nil || image.Kind != yaml.ScalarNode || image.Value == "" {
+				continue
+			}
+
+			if hasInterpolation(image.Value) {
+				continue
+			}
+
+			ref := resolver.NormalizeContainerRef(image.Value)
+			refs.Add(ref, image)
+		}
+	}
+
+	return nil
+}
+
+// hasInterpolation returns true if the value contains a Compose variable (e.g.
+// "$VAR" or "${VAR:-default}"). Escaped dollar signs ("$$") are not variables.
+func hasInterpolation(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '$' {
+			i++
+			continue
+		}
+		return true
+	}
+	return false
+}
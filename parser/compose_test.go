@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestCompose_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+services:
+`,
+			exp: nil,
+		},
+		{
+			name: "services",
+			in: `
+services:
+  web:
+    image: nginx:latest
+    ports:
+      - "80:80"
+  db:
+    image: postgres:16
+  cache:
+    image: redis
+`,
+			exp: []string{
+				"container://nginx:latest",
+				"container://postgres:16",
+				"container://redis",
+			},
+		},
+		{
+			name: "skips_build",
+			in: `
+services:
+  app:
+    build: .
+  app_tagged:
+    build:
+      context: .
+    image: example/app:dev
+  db:
+    image: postgres:16
+`,
+			exp: []string{
+				"container://postgres:16",
+			},
+		},
+		{
+			name: "skips_interpolation",
+			in: `
+services:
+  db:
+    image: postgres:${POSTGRES_VERSION:-16}
+  cache:
+    image: $REGISTRY/redis:7
+  web:
+    image: nginx:1.27
+`,
+			exp: []string{
+				"container://nginx:1.27",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Compose).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestHasInterpolation(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in  string
+		exp bool
+	}{
+		{in: "nginx:latest", exp: false},
+		{in: "nginx:${TAG}", exp: true},
+		{in: "nginx:${TAG:-latest}", exp: true},
+		{in: "$REGISTRY/nginx", exp: true},
+		{in: "nginx:$$literal", exp: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := hasInterpolation(tc.in), tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
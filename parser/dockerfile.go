@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/internal/dockerfile"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type Dockerfile struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (d *Dockerfile) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the image refs from Dockerfiles, which are decoded by the
+// dockerfile package. It extracts images from FROM instructions and the --from
+// flag of COPY and ADD instructions, skipping references to build stages and
+// the "scratch" image. Variables are expanded using the defaults of ARG
+// instructions before the first FROM, and images with variables that have no
+// default are skipped.
+//
+// Pinned images keep their tag (e.g. "golang:1.24@sha256:..."), and images
+// with variables keep the variables, since the version usually lives in the
+// ARG instruction.
+func (d *Dockerfile) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := d.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (d *Dockerfile) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	args := make(map[string]string, 4)
+	stages := make(map[string]struct{}, 4)
+	seenFrom := false
+
+	for _, instruction := range dockerfile.Instructions(node) {
+		name := mappingValue(instruction, dockerfile.KeyInstruction)
+		flags := mappingValue(instruction, dockerfile.KeyFlags)
+		arguments := mappingValue(instruction, dockerfile.KeyArguments)
+		if name == nil || arguments == nil {
+			continue
+		}
+
+		switch name.Value {
+		case "ARG":
+			// Only arguments declared before the first FROM can be used in FROM.
+			if seenFrom {
+				continue
+			}
+			for _, arg := range arguments.Content {
+				k, v, ok := strings.Cut(arg.Value, "=")
+				if !ok {
+					continue
+				}
+				if unquoted, err := strconv.Unquote(v); err == nil {
+					v = unquoted
+				}
+				args[k] = strings.Trim(v, "'")
+			}
+		case "FROM":
+			seenFrom = true
+			if len(arguments.Content) == 0 {
+				continue
+			}
+			d.parseImage(refs, args, stages, arguments.Content[0])
+
+			if len(arguments.Content) >= 3 && strings.EqualFold(arguments.Content[1].Value, "AS") {
+				stages[strings.ToLower(arguments.Content[2].Value)] = struct{}{}
+			}
+		case "COPY", "ADD":
+			from := mappingValue(flags, "from")
+			if from == nil {
+				continue
+			}
+			// Stages may also be referenced by their index.
+			if _, err := strconv.Atoi(from.Value); err == nil {
+				continue
+			}
+			d.parseImage(refs, args, stages, from)
+		}
+	}
+
+	return nil
+}
+
+// parseImage registers the image, unless it refers to a build stage.
+func (d *Dockerfile) parseImage(refs *RefsList, args map[string]string, stages map[string]struct{}, image *yaml.Node) {
+	expanded, ok := expandDockerfileArgs(image.Value, args)
+	if !ok || expanded == "" || strings.EqualFold(expanded, "scratch") {
+		return
+	}
+	if _, ok := stages[strings.ToLower(expanded)]; ok {
+		return
+	}
+
+	projection := dockerfileProjection(image.Value, args)
+	refs.AddProjected(resolver.NormalizeContainerRef(expanded), image, projection)
+}
+
+// dockerfileProjection returns a projection for the image with the given value.
+// Pinned images keep the tag and any variables of the original value, and
+// images with variables are not upgraded.
+func dockerfileProjection(value string, args map[string]string) *Projection {
+	base, _, _ := strings.Cut(value, "@")
+	hasArgs := strings.Contains(value, "$")
+
+	return &Projection{
+		Value: func(ref string) string {
+			if _, digest, ok := strings.Cut(ref, "@"); ok && isAbsolute(ref) {
+				return base + "@" + digest
+			}
+			if hasArgs {
+				return value
+			}
+			return ref
+		},
+		Ref: func(value string) string {
+			expanded, _ := expandDockerfileArgs(value, args)
+			return expanded
+		},
+	}
+}
+
+// expandDockerfileArgs expands the variables ("$NAME", "${NAME}" and
+// "${NAME:-default}") in s. It returns false if any variable has no value.
+func expandDockerfileArgs(s string, args map[string]string) (string, bool) {
+	var b strings.Builder
+
+	for {
+		idx := strings.IndexByte(s, '$')
+		if idx < 0 {
+			b.WriteString(s)
+			return b.String(), true
+		}
+		b.WriteString(s[:idx])
+		s = s[idx+1:]
+
+		var name, fallback string
+		var hasFallback bool
+		if rest, ok := strings.CutPrefix(s, "{"); ok {
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return "", false
+			}
+			name, s = rest[:end], rest[end+1:]
+
+			if before, after, ok := strings.Cut(name, ":-"); ok {
+				name, fallback, hasFallback = before, after, true
+			}
+		} else {
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			name, s = s[:end], s[end:]
+		}
+
+		if name == "" {
+			return "", false
+		}
+
+		v, ok := args[name]
+		if !ok || v == "" {
+			if !hasFallback {
+				return "", false
+			}
+			v = fallback
+		}
+		b.WriteString(v)
+	}
+}
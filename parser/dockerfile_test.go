@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/internal/dockerfile"
+)
+
+func TestDockerfile_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "empty",
+			in:   ``,
+			exp:  nil,
+		},
+		{
+			name: "from",
+			in: `
+FROM ubuntu:24.04
+FROM --platform=linux/amd64 golang:1.24 AS build
+FROM gcr.io/distroless/static@sha256:8dd8d3ca2cf283383304fd45a5c9c74d5f2cd9da8d3b077d720e264880077c65
+`,
+			exp: []string{
+				"container://gcr.io/distroless/static@sha256:8dd8d3ca2cf283383304fd45a5c9c74d5f2cd9da8d3b077d720e264880077c65",
+				"container://golang:1.24",
+				"container://ubuntu:24.04",
+			},
+		},
+		{
+			name: "copy_from",
+			in: `
+FROM golang:1.24 AS build
+FROM ubuntu:24.04
+COPY --from=build /bin/app /bin/app
+COPY --from=0 /bin/app /bin/app
+COPY --from=alpine:3.20 /bin/busybox /bin/busybox
+ADD --from=busybox /bin/sh /bin/sh
+`,
+			exp: []string{
+				"container://alpine:3.20",
+				"container://busybox",
+				"container://golang:1.24",
+				"container://ubuntu:24.04",
+			},
+		},
+		{
+			name: "stages",
+			in: `
+FROM golang:1.24 AS Build
+FROM build AS test
+FROM scratch
+COPY --from=BUILD /bin/app /bin/app
+`,
+			exp: []string{
+				"container://golang:1.24",
+			},
+		},
+		{
+			name: "args",
+			in: `
+ARG GO_VERSION=1.24
+ARG REGISTRY="gcr.io"
+ARG BASE
+FROM golang:${GO_VERSION}
+FROM $REGISTRY/distroless/static
+FROM ${BASE}
+FROM ${BASE:-ubuntu}:24.04
+ARG LATE=1.0
+FROM alpine:${LATE}
+`,
+			exp: []string{
+				"container://gcr.io/distroless/static",
+				"container://golang:1.24",
+				"container://ubuntu:24.04",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := dockerfile.Decode([]byte(strings.TrimSpace(tc.in)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			refs, err := new(Dockerfile).Parse(map[string]*yaml.Node{
+				"Dockerfile": doc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDockerfileProjection(t *testing.T) {
+	t.Parallel()
+
+	const digest = "sha256:6015f66923d7afbc53558d7ccffd325d43b4e249f41a6e93eef074c9505d2233"
+
+	args := map[string]string{"VERSION": "24.04"}
+
+	cases := []struct {
+		name     string
+		value    string
+		ref      string
+		expValue string
+		expRef   string
+	}{
+		{
+			name:     "pin",
+			value:    "ubuntu:24.04",
+			ref:      "ubuntu@" + digest,
+			expValue: "ubuntu:24.04@" + digest,
+			expRef:   "ubuntu:24.04",
+		},
+		{
+			name:     "pin_args",
+			value:    "ubuntu:${VERSION}",
+			ref:      "ubuntu@" + digest,
+			expValue: "ubuntu:${VERSION}@" + digest,
+			expRef:   "ubuntu:24.04",
+		},
+		{
+			name:     "upgrade",
+			value:    "ubuntu:24.04",
+			ref:      "ubuntu:26.04",
+			expValue: "ubuntu:26.04",
+			expRef:   "ubuntu:24.04",
+		},
+		{
+			name:     "upgrade_args",
+			value:    "ubuntu:${VERSION}",
+			ref:      "ubuntu:26.04",
+			expValue: "ubuntu:${VERSION}",
+			expRef:   "ubuntu:24.04",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := dockerfileProjection(tc.value, args)
+			if got, want := p.Value(tc.ref), tc.expValue; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+			if got, want := p.Ref(tc.value), tc.expRef; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
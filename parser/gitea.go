@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+// Gitea parses Gitea and Forgejo Actions workflows. The syntax mirrors GitHub
+// Actions, but "uses" refs are resolved against the Gitea or Forgejo instance
+// (or the instance in a full URL) instead of GitHub.
+type Gitea struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (g *Gitea) DenormalizeRef(ref string) string {
+	return new(Actions).DenormalizeRef(ref)
+}
+
+// Parse pulls the Gitea and Forgejo Actions refs from the documents.
+func (g *Gitea) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	actionsRefs, err := new(Actions).Parse(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs RefsList
+	for ref, refNodes := range actionsRefs.All() {
+		if rest, ok := strings.CutPrefix(ref, resolver.ActionsProtocol); ok {
+			ref = resolver.NormalizeGiteaRef(rest)
+		}
+
+		for _, node := range refNodes {
+			refs.Add(ref, node)
+		}
+	}
+
+	return &refs, nil
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestGitea_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+jobs:
+`,
+			exp: nil,
+		},
+		{
+			name: "uses",
+			in: `
+on: push
+jobs:
+  my_job:
+    container:
+      image: node:20
+    steps:
+      - uses: actions/checkout@v4
+      - uses: https://code.forgejo.org/actions/setup-go@v5
+      - uses: docker://alpine:3
+      - uses: ./local/action
+`,
+			exp: []string{
+				"container://alpine:3",
+				"container://node:20",
+				"gitea://actions/checkout@v4",
+				"gitea://https://code.forgejo.org/actions/setup-go@v5",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Gitea).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
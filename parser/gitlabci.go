@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	// Using banydonk/yaml instead of the default yaml pkg because the default
 	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
@@ -16,8 +18,9 @@ func (c *GitLabCI) DenormalizeRef(ref string) string {
 	return resolver.DenormalizeRef(ref)
 }
 
-// Parse pulls the image references from GitLab CI configuration files. It does
-// not support references with variables.
+// Parse pulls the image references from GitLab CI configuration files, and the
+// refs of included projects and CI/CD components. It does not support
+// references with variables.
 func (c *GitLabCI) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
 	var refs RefsList
 
@@ -55,6 +58,9 @@ func (c *GitLabCI) parseOne(refs *RefsList, m *yaml.Node) error {
 		if docMap.Kind != yaml.MappingNode {
 			continue
 		}
+
+		c.parseInclude(refs, mappingValue(docMap, "include"))
+
 		// jobs names
 		for i, keysMap := range docMap.Content {
 			// exclude global keywords
@@ -108,3 +114,68 @@ func (c *GitLabCI) parseOne(refs *RefsList, m *yaml.Node) error {
 
 	return nil
 }
+
+// parseInclude extracts the refs from the include keyword, which is either a
+// single include or a list of includes. Project includes are pinned through
+// their ref, and components are pinned in place. Other includes (e.g. local
+// files and remote URLs) do not have a ref.
+func (c *GitLabCI) parseInclude(refs *RefsList, include *yaml.Node) {
+	if include == nil {
+		return
+	}
+
+	entries := []*yaml.Node{include}
+	if include.Kind == yaml.SequenceNode {
+		entries = include.Content
+	}
+
+	for _, entry := range entries {
+		if project := mappingValue(entry, "project"); project != nil {
+			ref := mappingValue(entry, "ref")
+			if ref == nil || ref.Kind != yaml.ScalarNode || ref.Value == "" ||
+				strings.Contains(project.Value, "$") || strings.Contains(ref.Value, "$") {
+				continue
+			}
+
+			projection := versionProjection(strings.Trim(project.Value, "/"), "")
+			refs.AddProjected(resolver.NormalizeGitLabRef(projection.Ref(ref.Value)), ref, projection)
+		}
+
+		if component := mappingValue(entry, "component"); component != nil && component.Kind == yaml.ScalarNode {
+			if projection := gitlabComponentProjection(component.Value); projection != nil {
+				refs.AddProjected(resolver.NormalizeGitLabComponentRef(projection.Ref(component.Value)), component, projection)
+			}
+		}
+	}
+}
+
+// gitlabComponentProjection returns a projection for the CI/CD component (e.g.
+// "gitlab.com/my-org/my-project/my-component@1.0"), which is the host, the
+// project, and the name of the component. The ref is the project on that host.
+// It returns nil if the component cannot be pinned, including paths with fewer
+// than three segments after the host, since a project is always at least a
+// namespace and a name.
+func gitlabComponentProjection(value string) *Projection {
+	pth, version, ok := strings.Cut(value, "@")
+	if !ok || version == "" || version == "~latest" || strings.Contains(value, "$") {
+		return nil
+	}
+
+	segments := strings.Split(pth, "/")
+	if len(segments) < 4 || slices.Contains(segments, "") {
+		return nil
+	}
+	host := segments[0]
+	project := strings.Join(segments[1:len(segments)-1], "/")
+
+	return &Projection{
+		Value: func(ref string) string {
+			_, version, _ := strings.Cut(ref, "@")
+			return pth + "@" + version
+		},
+		Ref: func(value string) string {
+			_, version, _ := strings.Cut(value, "@")
+			return "https://" + host + "/" + project + "@" + version
+		},
+	}
+}
@@ -1,11 +1,14 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 func TestGitLabCI_Parse(t *testing.T) {
@@ -137,6 +140,61 @@ job2:
 				"container://selenium/standalone-firefox:latest",
 			},
 		},
+		{
+			name: "include_project",
+			in: `
+include:
+  - project: my-group/sub/templates
+    ref: v1.2
+    file: /templates/build.yml
+  - project: my-group/no-ref
+    file: /templates/build.yml
+  - project: my-group/$TEMPLATES
+    ref: main
+  - local: /templates/test.yml
+  - remote: https://example.com/ci.yml
+  - template: Auto-DevOps.gitlab-ci.yml
+`,
+			exp: []string{
+				"gitlab://my-group/sub/templates@v1.2",
+			},
+		},
+		{
+			name: "include_single",
+			in: `
+include:
+  project: my-group/templates
+  ref: 2541b1294d2704b0964813337f33b291d3f8596b
+  file: /templates/build.yml
+`,
+			exp: []string{
+				"gitlab://my-group/templates@2541b1294d2704b0964813337f33b291d3f8596b",
+			},
+		},
+		{
+			name: "include_component",
+			in: `
+include:
+  - component: gitlab.com/my-org/security-components/secret-detection@1.0
+  - component: gitlab.example.com/my-org/sub-group/components/deploy@2.1.0
+  - component: $CI_SERVER_FQDN/my-org/components/lint@1.0
+  - component: gitlab.com/my-org/components/test@~latest
+`,
+			exp: []string{
+				"gitlab-component://https://gitlab.com/my-org/security-components@1.0",
+				"gitlab-component://https://gitlab.example.com/my-org/sub-group/components@2.1.0",
+			},
+		},
+		{
+			name: "include_component_too_short",
+			in: `
+include:
+  - component: gitlab.example.com/my-org/comp@2.1.0
+  - component: gitlab.com/comp@1.0
+  - component: gitlab.com/my-org//comp@1.0
+`,
+			exp: nil,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -158,3 +216,48 @@ job2:
 		})
 	}
 }
+
+func TestGitLabCI_Pin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"gitlab://my-group/templates@v1.2": {
+			Resolved: "my-group/templates@c4a0b883114b00d8d76b479c820ce7950211c99b",
+		},
+		"gitlab-component://https://gitlab.com/my-org/components@1.0": {
+			Resolved: "https://gitlab.com/my-org/components@a12a3943b4bdde767164f792f33f40b04645d846",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := `
+include:
+  - project: my-group/templates
+    ref: v1.2
+    file: /templates/build.yml
+  - component: gitlab.com/my-org/components/lint@1.0
+`
+
+	exp := `
+include:
+  - project: my-group/templates
+    ref: c4a0b883114b00d8d76b479c820ce7950211c99b # ratchet:v1.2
+    file: /templates/build.yml
+  - component: gitlab.com/my-org/components/lint@a12a3943b4bdde767164f792f33f40b04645d846 # ratchet:gitlab.com/my-org/components/lint@1.0
+`
+
+	m := helperStringToYAML(t, in)
+	nodes := map[string]*yaml.Node{"test.yml": m}
+
+	if err := Pin(ctx, res, new(GitLabCI), nodes, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(exp); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+}
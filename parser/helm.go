@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type Helm struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (h *Helm) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the container images from Helm values files. Images are either a
+// string (e.g. "image: nginx:1.25"), or split across the "repository" and
+// "tag" keys of a mapping, with optional "registry" and "digest" keys:
+//
+//	image:
+//	  registry: docker.io
+//	  repository: nginx
+//	  tag: "1.25"
+//	  digest: ""
+//
+// If there is a "digest" key, the digest is pinned there and the tag is not
+// changed. Otherwise, the digest is appended to the tag (e.g.
+// "1.25@sha256:..."). Images that use templates (e.g. "{{ .Chart.AppVersion }}")
+// or an empty tag (which usually defaults to the chart's appVersion) are
+// ignored.
+func (h *Helm) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := h.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (h *Helm) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		h.findImages(refs, docMap)
+	}
+
+	return nil
+}
+
+// findImages recursively finds the images in the node.
+func (h *Helm) findImages(refs *RefsList, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if h.parseImage(refs, node) {
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "image" && value.Kind == yaml.ScalarNode {
+				if value.Value != "" && !strings.Contains(value.Value, "{{") {
+					ref := resolver.NormalizeContainerRef(value.Value)
+					refs.Add(ref, value)
+				}
+				continue
+			}
+
+			h.findImages(refs, value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			h.findImages(refs, item)
+		}
+	}
+}
+
+// parseImage adds the image if the mapping has the "repository" and "tag" or
+// "digest" keys. It returns true if the mapping is an image, even if it was
+// skipped.
+func (h *Helm) parseImage(refs *RefsList, node *yaml.Node) bool {
+	repository := mappingValue(node, "repository")
+	tag := mappingValue(node, "tag")
+	digest := mappingValue(node, "digest")
+	if repository == nil || repository.Kind != yaml.ScalarNode || (tag == nil && digest == nil) {
+		return false
+	}
+	if (tag != nil && tag.Kind != yaml.ScalarNode) || (digest != nil && digest.Kind != yaml.ScalarNode) {
+		return false
+	}
+
+	base := repository.Value
+	if registry := mappingValue(node, "registry"); registry != nil && registry.Value != "" {
+		base = strings.TrimSuffix(registry.Value, "/") + "/" + base
+	}
+
+	var tagValue string
+	if tag != nil {
+		tagValue = tag.Value
+	}
+
+	if repository.Value == "" || strings.Contains(base+tagValue, "{{") {
+		return true
+	}
+
+	if digest != nil {
+		if tagValue == "" && digest.Value == "" {
+			return true
+		}
+
+		projection := helmDigestProjection(base, tagValue)
+		refs.AddProjected(resolver.NormalizeContainerRef(projection.Ref(digest.Value)), digest, projection)
+		return true
+	}
+
+	if tagValue == "" {
+		return true
+	}
+
+	projection := helmTagProjection(base, tagValue)
+	refs.AddProjected(resolver.NormalizeContainerRef(projection.Ref(tagValue)), tag, projection)
+	return true
+}
+
+// helmTagProjection returns a projection for the tag of the image with the
+// given base. Pinned tags keep the original tag (e.g. "1.25@sha256:...").
+func helmTagProjection(base, tag string) *Projection {
+	return &Projection{
+		Value: func(ref string) string {
+			if _, digest, ok := strings.Cut(ref, "@"); ok && isAbsolute(ref) {
+				return tag + "@" + digest
+			}
+			return strings.TrimPrefix(ref, base+":")
+		},
+		Ref: func(value string) string {
+			return base + ":" + value
+		},
+	}
+}
+
+// emptyOriginal records that the original digest was empty, since an empty
+// ratchet comment is ignored.
+const emptyOriginal = `""`
+
+// helmDigestProjection returns a projection for the digest of the image with
+// the given base and tag. The original value of an empty digest is recorded as
+// emptyOriginal, so it can be unpinned.
+func helmDigestProjection(base, tag string) *Projection {
+	name := base
+	if tag != "" {
+		name += ":" + tag
+	}
+
+	return &Projection{
+		Value: func(ref string) string {
+			if _, digest, ok := strings.Cut(ref, "@"); ok && isAbsolute(ref) {
+				return digest
+			}
+			return ""
+		},
+		Ref: func(value string) string {
+			if value == "" || value == emptyOriginal {
+				return name
+			}
+			return name + "@" + value
+		},
+		Comment: func(comment, original string) string {
+			if original == "" {
+				original = emptyOriginal
+			}
+			return appendOriginalToComment(comment, original)
+		},
+		Unpinned: func(original string) string {
+			if original == emptyOriginal {
+				return ""
+			}
+			return original
+		},
+	}
+}
@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+const testHelmDigest = "sha256:ab1dc7a2e5a3fbc39e3e6e3b3a7bb2b3eb86d17adda1a16c7d3a8b95b7bbc2e8"
+
+func TestHelm_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+replicaCount: 1
+`,
+			exp: nil,
+		},
+		{
+			name: "repository_and_tag",
+			in: `
+image:
+  repository: nginx
+  tag: "1.25"
+  pullPolicy: IfNotPresent
+`,
+			exp: []string{
+				"container://nginx:1.25",
+			},
+		},
+		{
+			name: "registry_and_digest",
+			in: `
+image:
+  registry: docker.io/
+  repository: bitnami/redis
+  tag: 7.2.4
+  digest: ""
+metrics:
+  image:
+    registry: ghcr.io
+    repository: my-org/exporter
+    tag: v1
+    digest: ` + testHelmDigest + `
+`,
+			exp: []string{
+				"container://docker.io/bitnami/redis:7.2.4",
+				"container://ghcr.io/my-org/exporter:v1@" + testHelmDigest,
+			},
+		},
+		{
+			name: "nested_and_strings",
+			in: `
+controller:
+  sidecars:
+    - name: proxy
+      image:
+        repository: envoyproxy/envoy
+        tag: v1.30.1
+  initImage: busybox:1.36
+  image: alpine:3.20
+`,
+			exp: []string{
+				"container://alpine:3.20",
+				"container://envoyproxy/envoy:v1.30.1",
+			},
+		},
+		{
+			name: "skips_templates_and_app_version",
+			in: `
+image:
+  repository: nginx
+  tag: ""
+sidecar:
+  image:
+    repository: busybox
+    tag: "{{ .Chart.AppVersion }}"
+other:
+  image: "{{ .Values.global.image }}"
+`,
+			exp: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Helm).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestHelm_Pin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"container://nginx:1.25": {
+			Resolved: "index.docker.io/library/nginx@" + testHelmDigest,
+		},
+		"container://docker.io/bitnami/redis:7.2.4": {
+			Resolved: "index.docker.io/bitnami/redis@" + testHelmDigest,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "tag",
+			in: `
+image:
+  repository: nginx
+  tag: 1.25 # web server
+`,
+			exp: `
+image:
+  repository: nginx
+  tag: 1.25@` + testHelmDigest + ` # web server ratchet:1.25
+`,
+		},
+		{
+			name: "digest",
+			in: `
+image:
+  registry: docker.io
+  repository: bitnami/redis
+  tag: 7.2.4
+  digest: ""
+`,
+			exp: `
+image:
+  registry: docker.io
+  repository: bitnami/redis
+  tag: 7.2.4
+  digest: "` + testHelmDigest + `" # ratchet:""
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := helperStringToYAML(t, tc.in)
+
+			if err := Pin(ctx, res, new(Helm), map[string]*yaml.Node{"test.yml": m}, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := helperYAMLToString(t, m), strings.TrimSpace(tc.exp); got != want {
+				t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+
+			// Pinned images are absolute.
+			violations, err := Lint(ctx, new(Helm), map[string]*yaml.Node{"test.yml": m})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(violations) > 0 {
+				t.Errorf("expected no violations, got %d", len(violations))
+			}
+
+			if err := Unpin(ctx, new(Helm), map[string]*yaml.Node{"test.yml": m}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := helperYAMLToString(t, m), strings.TrimSpace(tc.in); got != want {
+				t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"fmt"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+// kubernetesPodSpecPaths is the path to the pod spec for each workload kind.
+var kubernetesPodSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"PodTemplate":           {"template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// kubernetesContainerFields are the fields in a pod spec that contain lists of
+// containers.
+var kubernetesContainerFields = []string{"initContainers", "containers", "ephemeralContainers"}
+
+type Kubernetes struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (k *Kubernetes) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the container images from Kubernetes workload manifests. Only
+// the container image fields of known workload kinds are considered, so other
+// "image" keys (e.g. in custom resources) are ignored.
+func (k *Kubernetes) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := k.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (k *Kubernetes) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		k.parseObject(refs, docMap)
+	}
+
+	return nil
+}
+
+// parseObject extracts the images from the Kubernetes object, including the
+// items of a List.
+func (k *Kubernetes) parseObject(refs *RefsList, object *yaml.Node) {
+	if mappingValue(object, "apiVersion") == nil {
+		return
+	}
+
+	kind := mappingValue(object, "kind")
+	if kind == nil {
+		return
+	}
+
+	if kind.Value == "List" {
+		if items := mappingValue(object, "items"); items != nil && items.Kind == yaml.SequenceNode {
+			for _, item := range items.Content {
+				k.parseObject(refs, item)
+			}
+		}
+		return
+	}
+
+	pth, ok := kubernetesPodSpecPaths[kind.Value]
+	if !ok {
+		return
+	}
+
+	podSpec := lookupPath(object, pth)
+	if podSpec == nil {
+		return
+	}
+
+	for _, field := range kubernetesContainerFields {
+		containers := mappingValue(podSpec, field)
+		if containers == nil || containers.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, container := range containers.Content {
+			image := mappingValue(container, "image")
+			if image == nil || image.Kind != yaml.ScalarNode || image.Value == "" {
+				continue
+			}
+
+			ref := resolver.NormalizeContainerRef(image.Value)
+			refs.Add(ref, image)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestKubernetes_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+apiVersion: v1
+kind: ConfigMap
+data:
+  image: not-an-image
+`,
+			exp: nil,
+		},
+		{
+			name: "pod",
+			in: `
+apiVersion: v1
+kind: Pod
+spec:
+  initContainers:
+    - name: init
+      image: busybox:1.36
+  containers:
+    - name: app
+      image: nginx:1.27
+  ephemeralContainers:
+    - name: debug
+      image: alpine:3
+`,
+			exp: []string{
+				"container://alpine:3",
+				"container://busybox:1.36",
+				"container://nginx:1.27",
+			},
+		},
+		{
+			name: "deployment",
+			in: `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    metadata:
+      annotations:
+        image: not-an-image
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.27
+`,
+			exp: []string{
+				"container://nginx:1.27",
+			},
+		},
+		{
+			name: "cronjob",
+			in: `
+apiVersion: batch/v1
+kind: CronJob
+spec:
+  schedule: "* * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: job
+              image: ubuntu:20.04
+`,
+			exp: []string{
+				"container://ubuntu:20.04",
+			},
+		},
+		{
+			name: "list",
+			in: `
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: StatefulSet
+    spec:
+      template:
+        spec:
+          containers:
+            - name: db
+              image: postgres:16
+  - apiVersion: apps/v1
+    kind: DaemonSet
+    spec:
+      template:
+        spec:
+          containers:
+            - name: agent
+              image: fluent/fluent-bit:3
+`,
+			exp: []string{
+				"container://fluent/fluent-bit:3",
+				"container://postgres:16",
+			},
+		},
+		{
+			name: "custom_resource",
+			in: `
+apiVersion: example.com/v1
+kind: Widget
+spec:
+  containers:
+    - image: nginx:1.27
+`,
+			exp: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Kubernetes).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
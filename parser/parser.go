@@ -30,23 +30,64 @@ type Parser interface {
 }
 
 var parserFactory = map[string]func() Parser{
-	"actions":    func() Parser { return new(Actions) },
-	"circleci":   func() Parser { return new(CircleCI) },
-	"cloudbuild": func() Parser { return new(CloudBuild) },
-	"drone":      func() Parser { return new(Drone) },
-	"gitlabci":   func() Parser { return new(GitLabCI) },
-	"tekton":     func() Parser { return new(Tekton) },
+	"actions":        func() Parser { return new(Actions) },
+	"argo":           func() Parser { return new(Argo) },
+	"auto":           func() Parser { return new(Auto) },
+	"azurepipelines": func() Parser { return new(AzurePipelines) },
+	"bitbucket":      func() Parser { return new(Bitbucket) },
+	"buildkite":      func() Parser { return new(Buildkite) },
+	"circleci":       func() Parser { return new(CircleCI) },
+	"cloudbuild":     func() Parser { return new(CloudBuild) },
+	"compose":        func() Parser { return new(Compose) },
+	"dockerfile":     func() Parser { return new(Dockerfile) },
+	"drone":          func() Parser { return new(Drone) },
+	"gitea":          func() Parser { return new(Gitea) },
+	"gitlabci":       func() Parser { return new(GitLabCI) },
+	"helm":           func() Parser { return new(Helm) },
+	"kubernetes":     func() Parser { return new(Kubernetes) },
+	"precommit":      func() Parser { return new(PreCommit) },
+	"tekton":         func() Parser { return new(Tekton) },
+	"woodpecker":     func() Parser { return new(Woodpecker) },
 }
 
 var parsers = sync.OnceValue(func() []string {
 	return slices.Sorted(maps.Keys(parserFactory))
 })
 
+// Options are the options for parsers that support them.
+type Options struct {
+	// PreCommitFrozen records the original revision of pre-commit refs in
+	// pre-commit's own style (e.g. "# frozen: v4.5.0").
+	PreCommitFrozen bool
+}
+
+// apply configures the parser with the options.
+func (o *Options) apply(p Parser) {
+	if o == nil {
+		return
+	}
+
+	switch p := p.(type) {
+	case *Auto:
+		p.options = o
+	case *PreCommit:
+		p.Frozen = o.PreCommitFrozen
+	}
+}
+
 // For returns the parser that corresponds to the given name.
 func For(ctx context.Context, name string) (Parser, error) {
+	return ForWithOptions(ctx, name, nil)
+}
+
+// ForWithOptions returns the parser that corresponds to the given name,
+// configured with the given options.
+func ForWithOptions(ctx context.Context, name string, opts *Options) (Parser, error) {
 	typ := strings.ToLower(strings.TrimSpace(name))
 	if v, ok := parserFactory[typ]; ok {
-		return v(), nil
+		p := v()
+		opts.apply(p)
+		return p, nil
 	}
 	return nil, fmt.Errorf("unknown parser %q, valid parsers are %q",
 		typ, List())
@@ -99,18 +140,21 @@ func Lint(ctx context.Context, parser Parser, nodes map[string]*yaml.Node) ([]*l
 		}
 		refs := refsList.All()
 
-		for _, nodes := range refs {
+		for ref, nodes := range refs {
 			for _, node := range nodes {
 				if shouldExclude(node.LineComment) {
 					continue
 				}
 
-				if !isAbsolute(node.Value) {
+				// Check the ref instead of the node value, since some nodes only
+				// contain part of the ref (e.g. "docker#<sha>").
+				if !isAbsolute(ref) {
 					violations = append(violations, &linter.Violation{
 						Filename: filename,
 						Contents: node.Value,
 						Line:     node.Line,
 						Column:   node.Column,
+						Rule:     linter.RuleUnpinned,
 					})
 				}
 			}
@@ -121,6 +165,286 @@ func Lint(ctx context.Context, parser Parser, nodes map[string]*yaml.Node) ([]*l
 	return violations, nil
 }
 
+// Impostors iterates over all pinned GitHub Actions references in the yaml and
+// verifies that each commit is reachable from the default branch of the
+// referenced repository, or from a branch or tag that matches the version in
+// its ratchet comment. It returns a violation for every reference that is not.
+//
+// It ignores "ratchet:exclude" nodes from the lookup.
+func Impostors(ctx context.Context, verifier resolver.CommitVerifier, parser Parser, nodes map[string]*yaml.Node, concurrency int64) ([]*linter.Violation, error) {
+	type fileNode struct {
+		filename string
+		node     *yaml.Node
+	}
+
+	type candidate struct {
+		ref     string
+		version string
+	}
+
+	// Parse files individually so we know which file each node is in, but only
+	// verify each reference and version once.
+	candidates := make(map[candidate][]*fileNode, 8)
+	for filename, document := range nodes {
+		refsList, err := parser.Parse(map[string]*yaml.Node{
+			filename: document,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for ref, nodes := range refsList.All() {
+			if !strings.HasPrefix(ref, resolver.ActionsProtocol) || !isAbsolute(ref) {
+				continue
+			}
+
+			for _, node := range nodes {
+				if shouldExclude(node.LineComment) {
+					continue
+				}
+
+				// The version is the part of the original ref after the "@" (e.g.
+				// "v4" in "actions/checkout@v4").
+				original, _ := refsList.original(node)
+				if p := refsList.Projection(node); p != nil && original != "" {
+					original = p.Ref(original)
+				}
+				_, version, _ := strings.Cut(original, "@")
+
+				key := candidate{ref: ref, version: version}
+				candidates[key] = append(candidates[key], &fileNode{
+					filename: filename,
+					node:     node,
+				})
+			}
+		}
+	}
+
+	sem := semaphore.NewWeighted(concurrency)
+
+	var lock sync.Mutex
+	var merr error
+	var violations []*linter.Violation
+
+	for key, fileNodes := range candidates {
+		key := key
+		fileNodes := fileNodes
+
+		if err := sem.Acquire(ctx, 1); err != nil {
+			return nil, fmt.Errorf("failed to acquire semaphore: %w", err)
+		}
+
+		go func() {
+			defer sem.Release(1)
+
+			ok, err := verifier.VerifyCommit(ctx, key.ref, key.version)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				merr = errors.Join(merr, fmt.Errorf("failed to verify %q: %w", key.ref, err))
+				return
+			}
+			if ok {
+				return
+			}
+
+			for _, fn := range fileNodes {
+				violations = append(violations, &linter.Violation{
+					Filename: fn.filename,
+					Contents: fn.node.Value,
+					Line:     fn.node.Line,
+					Column:   fn.node.Column,
+					Rule:     linter.RuleImpostorCommit,
+				})
+			}
+		}()
+	}
+
+	if err := sem.Acquire(ctx, concurrency); err != nil {
+		return nil, fmt.Errorf("failed to wait for semaphore: %w", err)
+	}
+
+	if merr != nil {
+		return nil, merr
+	}
+
+	sortViolations(violations)
+	return violations, nil
+}
+
+// Verify iterates over all pinned references in the yaml that have a ratchet
+// comment, resolves the version in the comment, and checks that it matches the
+// pin. It returns a violation for every pin that is older than the comment
+// (stale) or unrelated to it (suspicious).
+//
+// It ignores "ratchet:exclude" nodes from the lookup.
+func Verify(ctx context.Context, res resolver.Resolver, comparer resolver.CommitComparer, parser Parser, nodes map[string]*yaml.Node, concurrency int64) ([]*linter.Violation, error) {
+	type pair struct {
+		pinned   string
+		original string
+	}
+
+	type fileNode struct {
+		filename string
+		node     *yaml.Node
+	}
+
+	// Parse files individually so we know which file each node is in, but only
+	// resolve each pin and comment pair once.
+	candidates := make(map[pair][]*fileNode, 8)
+	for filename, document := range nodes {
+		refsList, err := parser.Parse(map[string]*yaml.Node{
+			filename: document,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for ref, nodes := range refsList.All() {
+			if !isAbsolute(ref) {
+				continue
+			}
+
+			denormRef := resolver.DenormalizeRef(ref)
+			protocol := strings.TrimSuffix(ref, denormRef)
+
+			for _, node := range nodes {
+				if shouldExclude(node.LineComment) {
+					continue
+				}
+
+				original, _ := refsList.original(node)
+				if original == "" {
+					continue
+				}
+
+				// The comment records the entire original value, which may contain
+				// more or less than the ref (e.g. "docker://"), so convert it back
+				// into a ref.
+				if p := refsList.Projection(node); p != nil {
+					original = p.Ref(original)
+				} else {
+					idx := strings.Index(node.Value, denormRef)
+					if idx < 0 {
+						continue
+					}
+					prefix, suffix := node.Value[:idx], node.Value[idx+len(denormRef):]
+					if !strings.HasPrefix(original, prefix) || !strings.HasSuffix(original, suffix) {
+						continue
+					}
+					original = strings.TrimSuffix(strings.TrimPrefix(original, prefix), suffix)
+				}
+
+				key := pair{pinned: ref, original: protocol + original}
+				candidates[key] = append(candidates[key], &fileNode{
+					filename: filename,
+					node:     node,
+				})
+			}
+		}
+	}
+
+	sem := semaphore.NewWeighted(concurrency)
+
+	var lock sync.Mutex
+	var merr error
+	var violations []*linter.Violation
+
+	for key, fileNodes := range candidates {
+		key := key
+		fileNodes := fileNodes
+
+		if err := sem.Acquire(ctx, 1); err != nil {
+			return nil, fmt.Errorf("failed to acquire semaphore: %w", err)
+		}
+
+		go func() {
+			defer sem.Release(1)
+
+			rule, err := verifyPin(ctx, res, comparer, key.pinned, key.original)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				merr = errors.Join(merr, fmt.Errorf("failed to verify %q: %w", key.pinned, err))
+				return
+			}
+			if rule == "" {
+				return
+			}
+
+			for _, fn := range fileNodes {
+				violations = append(violations, &linter.Violation{
+					Filename: fn.filename,
+					Contents: fn.node.Value,
+					Line:     fn.node.Line,
+					Column:   fn.node.Column,
+					Rule:     rule,
+				})
+			}
+		}()
+	}
+
+	if err := sem.Acquire(ctx, concurrency); err != nil {
+		return nil, fmt.Errorf("failed to wait for semaphore: %w", err)
+	}
+
+	if merr != nil {
+		return nil, merr
+	}
+
+	sortViolations(violations)
+	return violations, nil
+}
+
+// verifyPin resolves the original ref and compares it to the pinned ref. It
+// returns the violated rule, or the empty string if the pin is current.
+func verifyPin(ctx context.Context, res resolver.Resolver, comparer resolver.CommitComparer, pinned, original string) (linter.Rule, error) {
+	resolved, err := res.Resolve(ctx, original)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", original, err)
+	}
+
+	if strings.EqualFold(pinnedVersion(pinned), pinnedVersion(resolved)) {
+		return "", nil
+	}
+
+	protocol := strings.TrimSuffix(pinned, resolver.DenormalizeRef(pinned))
+	ok, err := comparer.IsAncestor(ctx, pinned, protocol+resolver.DenormalizeRef(resolved))
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return linter.RuleStalePin, nil
+	}
+	return linter.RuleSuspiciousPin, nil
+}
+
+// pinnedVersion returns the absolute version (SHA or digest) of a pinned ref.
+func pinnedVersion(ref string) string {
+	if idx := strings.LastIndex(ref, "@"); idx >= 0 {
+		return ref[idx+1:]
+	}
+	return ref
+}
+
+// sortViolations sorts the violations by position, for stable output when they
+// were collected concurrently.
+func sortViolations(violations []*linter.Violation) {
+	slices.SortFunc(violations, func(a, b *linter.Violation) int {
+		if a.Filename != b.Filename {
+			return strings.Compare(a.Filename, b.Filename)
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+}
+
 // Pin extracts all references from the given YAML document and resolves them
 // using the given resolver, updating the associated YAML nodes.
 func Pin(ctx context.Context, res resolver.Resolver, parser Parser, nodes map[string]*yaml.Node, concurrency int64) error {
@@ -181,8 +505,8 @@ func Pin(ctx context.Context, res resolver.Resolver, parser Parser, nodes map[st
 			denormRef := resolver.DenormalizeRef(ref)
 
 			for _, node := range nodes {
-				node.LineComment = appendOriginalToComment(node.LineComment, node.Value)
-				node.Value = strings.Replace(node.Value, denormRef, resolved, 1)
+				node.LineComment = refsList.annotate(node, node.Value)
+				setValue(node, refsList.replace(node, denormRef, resolved))
 			}
 		}()
 	}
@@ -243,12 +567,14 @@ func Upgrade(ctx context.Context, res resolver.Resolver, parser Parser, nodes ma
 				merrLock.Unlock()
 			}
 
-			denormRef := parser.DenormalizeRef(ref)
-			denormLatest := parser.DenormalizeRef(latest)
+			denormRef := resolver.DenormalizeRef(ref)
+			denormLatest := resolver.DenormalizeRef(latest)
 
+			// The comment records the upgraded value as it appears in the node,
+			// since the node may contain more than the ref (e.g. "docker://").
 			for _, node := range nodes {
-				node.LineComment = appendOriginalToComment(node.LineComment, denormLatest)
-				node.Value = strings.Replace(node.Value, denormRef, denormLatest, 1)
+				setValue(node, refsList.replace(node, denormRef, denormLatest))
+				node.LineComment = refsList.annotate(node, node.Value)
 			}
 		}()
 	}
@@ -263,19 +589,25 @@ func Upgrade(ctx context.Context, res resolver.Resolver, parser Parser, nodes ma
 // Unpin removes any pinned references and updates the actual YAML to be the
 // original reference, leaving any other comment intact. This effectively
 // replaces the YAML with the cached comment, which could result in losing the
-// current pin.
+// current pin. The parser is used to read comments that record the original
+// value in a parser-specific style (e.g. pre-commit's "# frozen: v4.5.0").
 //
 // This function does not make any outbound network calls and relies solely on
 // information in the document.
-func Unpin(ctx context.Context, files map[string]*yaml.Node) error {
+func Unpin(ctx context.Context, parser Parser, files map[string]*yaml.Node) error {
+	refsList, err := parser.Parse(files)
+	if err != nil {
+		return err
+	}
+
 	nodes := make([]*yaml.Node, 0, len(files))
 	for _, node := range files {
 		nodes = append(nodes, node)
 	}
-	return unpin(ctx, nodes)
+	return unpin(ctx, refsList, nodes)
 }
 
-func unpin(ctx context.Context, nodes []*yaml.Node) error {
+func unpin(ctx context.Context, refsList *RefsList, nodes []*yaml.Node) error {
 	for _, node := range nodes {
 		select {
 		case <-ctx.Done():
@@ -284,13 +616,16 @@ func unpin(ctx context.Context, nodes []*yaml.Node) error {
 		}
 
 		if node.LineComment != "" && !shouldExclude(node.LineComment) {
-			if v, rest := extractOriginalFromComment(node.LineComment); v != "" {
+			if v, rest := refsList.original(node); v != "" {
+				if p := refsList.Projection(node); p != nil && p.Unpinned != nil {
+					v = p.Unpinned(v)
+				}
 				node.Value = v
 				node.LineComment = rest
 			}
 		}
 
-		if err := unpin(ctx, node.Content); err != nil {
+		if err := unpin(ctx, refsList, node.Content); err != nil {
 			return err
 		}
 	}
@@ -326,14 +661,82 @@ func extractOriginalFromComment(comment string) (string, string) {
 		return "", comment
 	}
 
+	// Preserve any comment text before the ratchet value, ignoring the leading
+	// comment marker itself.
+	before := strings.TrimSpace(comment[:idx])
+	if before == "#" {
+		before = ""
+	}
+
 	rest := comment[idx+len(ratchetPrefix):]
 	parts := strings.SplitN(rest, " ", 2)
 	switch len(parts) {
 	case 1:
-		return parts[0], ""
+		return parts[0], before
 	case 2:
-		return parts[0], parts[1]
+		if before == "" {
+			return parts[0], parts[1]
+		}
+		return parts[0], before + " " + parts[1]
 	default:
 		panic(fmt.Sprintf("impossible number of parts to extract %q", rest))
 	}
 }
+
+// setValue sets the value of the scalar node. The new value may not have the
+// same type as the original (e.g. the float "1.25" is pinned to
+// "1.25@sha256:..."), so any non-string tag is cleared for the encoder to
+// infer.
+func setValue(node *yaml.Node, value string) {
+	if node.Value == value {
+		return
+	}
+	if node.Tag != "!!str" {
+		node.Tag = ""
+	}
+	node.Value = value
+}
+
+// mappingValue returns the value for the given key in the mapping node, or nil
+// if the node is not a mapping or does not contain the key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lookupPath returns the value at the path of mapping keys, or nil if any key
+// is missing.
+func lookupPath(node *yaml.Node, pth []string) *yaml.Node {
+	for _, key := range pth {
+		node = mappingValue(node, key)
+	}
+	return node
+}
+
+// githubRepo returns the GitHub repository ("owner/repo") for the git URL (e.g.
+// "https://github.com/owner/repo.git" or "git@github.com:owner/repo"). It
+// returns the empty string for URLs that are not hosted on GitHub.
+func githubRepo(source string) string {
+	for _, prefix := range []string{"https://", "http://", "ssh://git@", "git@"} {
+		source = strings.TrimPrefix(source, prefix)
+	}
+
+	rest, ok := strings.CutPrefix(source, "github.com")
+	if !ok || (rest != "" && rest[0] != ':' && rest[0] != '/') {
+		return ""
+	}
+
+	rest = strings.TrimLeft(rest, ":/")
+	rest = strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git")
+	if strings.Count(rest, "/") != 1 || strings.HasPrefix(rest, "/") || strings.HasSuffix(rest, "/") {
+		return ""
+	}
+	return rest
+}
@@ -3,12 +3,15 @@ package parser
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 
 	// Using banydonk/yaml instead of the default yaml pkg because the default
 	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
 	"github.com/braydonk/yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sethvargo/ratchet/linter"
 	"github.com/sethvargo/ratchet/resolver"
 )
 
@@ -49,6 +52,26 @@ jobs:
 `,
 			err: `found 1 unpinned refs: ["good/repo@v0"]`,
 		},
+		{
+			name: "bad_images",
+			in: `
+jobs:
+  my_job:
+    container: 'node:18'
+    services:
+      redis: 'redis:7'
+`,
+			err: `found 2 unpinned refs: ["node:18" "redis:7"]`,
+		},
+		{
+			name: "bad_docker_action",
+			in: `
+runs:
+  using: 'docker'
+  image: 'docker://alpine:3.20'
+`,
+			err: `found 1 unpinned refs: ["docker://alpine:3.20"]`,
+		},
 		{
 			name: "exclude",
 			in: `
@@ -83,6 +106,263 @@ jobs:
 	}
 }
 
+type testVerifier map[string]bool
+
+func (v testVerifier) VerifyCommit(ctx context.Context, ref, version string) (bool, error) {
+	key := ref
+	if version != "" {
+		key = ref + "#" + version
+	}
+
+	ok, found := v[key]
+	if !found {
+		return false, fmt.Errorf("unexpected ref %q", key)
+	}
+	return ok, nil
+}
+
+func TestImpostors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	par := new(Actions)
+
+	verifier := testVerifier{
+		"actions://good/repo@2541b1294d2704b0964813337f33b291d3f8596b": true,
+		"actions://bad/repo@a12a3943b4bdde767164f792f33f40b04645d846":  false,
+
+		"actions://good/repo@2541b1294d2704b0964813337f33b291d3f8596b#v2": true,
+		"actions://bad/repo@a12a3943b4bdde767164f792f33f40b04645d846#v1":  false,
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []*linter.Violation
+	}{
+		{
+			name: "verified",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@2541b1294d2704b0964813337f33b291d3f8596b'
+`,
+		},
+		{
+			name: "impostor",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@2541b1294d2704b0964813337f33b291d3f8596b'
+      - uses: 'bad/repo@a12a3943b4bdde767164f792f33f40b04645d846'
+`,
+			exp: []*linter.Violation{
+				{
+					Filename: "test.yml",
+					Contents: "bad/repo@a12a3943b4bdde767164f792f33f40b04645d846",
+					Line:     5,
+					Column:   15,
+					Rule:     linter.RuleImpostorCommit,
+				},
+			},
+		},
+		{
+			name: "ratchet_comment_version",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@2541b1294d2704b0964813337f33b291d3f8596b' # ratchet:good/repo@v2
+      - uses: 'bad/repo@a12a3943b4bdde767164f792f33f40b04645d846' # ratchet:bad/repo@v1
+`,
+			exp: []*linter.Violation{
+				{
+					Filename: "test.yml",
+					Contents: "bad/repo@a12a3943b4bdde767164f792f33f40b04645d846",
+					Line:     5,
+					Column:   15,
+					Rule:     linter.RuleImpostorCommit,
+				},
+			},
+		},
+		{
+			name: "skips_unpinned_and_containers",
+			in: `
+jobs:
+  my_job:
+    container: 'ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724'
+    steps:
+      - uses: 'bad/repo@v1'
+`,
+		},
+		{
+			name: "exclude",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'bad/repo@a12a3943b4bdde767164f792f33f40b04645d846' # ratchet:exclude
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			violations, err := Impostors(ctx, verifier, par, nodes, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(violations, tc.exp); diff != "" {
+				t.Errorf("unexpected violations (+got, -want):\n%s", diff)
+			}
+		})
+	}
+}
+
+type testComparer map[string]bool
+
+func (c testComparer) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	ok, found := c[ancestor+"..."+ref]
+	if !found {
+		return false, fmt.Errorf("unexpected comparison %q to %q", ancestor, ref)
+	}
+	return ok, nil
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	par := new(Actions)
+
+	const (
+		currentSHA   = "2541b1294d2704b0964813337f33b291d3f8596b"
+		oldSHA       = "a12a3943b4bdde767164f792f33f40b04645d846"
+		unrelatedSHA = "5a4ac9002d0be2fb38bd78e4b4dbde5606d7042f"
+		digest       = "sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724"
+	)
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://good/repo@v1": {
+			Resolved: "good/repo@" + currentSHA,
+		},
+		"container://ubuntu:20.04": {
+			Resolved: "index.docker.io/library/ubuntu@" + digest,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comparer := testComparer{
+		"actions://good/repo@" + oldSHA + "...actions://good/repo@" + currentSHA:       true,
+		"actions://good/repo@" + unrelatedSHA + "...actions://good/repo@" + currentSHA: false,
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []*linter.Violation
+	}{
+		{
+			name: "current",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'docker://ubuntu@` + digest + `' # ratchet:docker://ubuntu:20.04
+      - uses: 'good/repo@` + currentSHA + `' # ratchet:good/repo@v1
+      - uses: 'good/repo@` + currentSHA + `' # Some comment ratchet:good/repo@v1
+`,
+		},
+		{
+			name: "stale",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@` + oldSHA + `' # ratchet:good/repo@v1
+`,
+			exp: []*linter.Violation{
+				{
+					Filename: "test.yml",
+					Contents: "good/repo@" + oldSHA,
+					Line:     4,
+					Column:   15,
+					Rule:     linter.RuleStalePin,
+				},
+			},
+		},
+		{
+			name: "suspicious",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@` + unrelatedSHA + `' # ratchet:good/repo@v1
+`,
+			exp: []*linter.Violation{
+				{
+					Filename: "test.yml",
+					Contents: "good/repo@" + unrelatedSHA,
+					Line:     4,
+					Column:   15,
+					Rule:     linter.RuleSuspiciousPin,
+				},
+			},
+		},
+		{
+			name: "skips_without_comment",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@` + unrelatedSHA + `'
+      - uses: 'good/repo@v2'
+`,
+		},
+		{
+			name: "exclude",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@` + unrelatedSHA + `' # ratchet:good/repo@v1 ratchet:exclude
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			violations, err := Verify(ctx, res, comparer, par, nodes, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(violations, tc.exp); diff != "" {
+				t.Errorf("unexpected violations (+got, -want):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestPin(t *testing.T) {
 	t.Parallel()
 
@@ -107,6 +387,9 @@ func TestPin(t *testing.T) {
 		"container://ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724": {
 			Resolved: "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
 		},
+		"container://ubuntu:20.04": {
+			Resolved: "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
+		},
 	}, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -142,6 +425,21 @@ jobs:
   my_job:
     steps:
       - uses: 'good/repo@a12a3943' # ratchet:good/repo@v0
+`,
+		},
+		{
+			name: "frozen_comment_is_not_precommit",
+			in: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@v1' # frozen: do not bump
+`,
+			exp: `
+jobs:
+  my_job:
+    steps:
+      - uses: 'good/repo@b12a3943' # frozen: do not bump ratchet:good/repo@v1
 `,
 		},
 		{
@@ -172,6 +470,36 @@ jobs:
   my_job:
     steps:
       - uses: 'good/repo@a12a3943' # this is a comment ratchet:good/repo@v0
+`,
+		},
+		{
+			name: "container_images",
+			in: `
+jobs:
+  my_job:
+    container: 'ubuntu:20.04'
+    services:
+      ubuntu: 'ubuntu:20.04'
+`,
+			exp: `
+jobs:
+  my_job:
+    container: 'ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724' # ratchet:ubuntu:20.04
+    services:
+      ubuntu: 'ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724' # ratchet:ubuntu:20.04
+`,
+		},
+		{
+			name: "docker_action",
+			in: `
+runs:
+  using: 'docker'
+  image: 'docker://ubuntu:20.04'
+`,
+			exp: `
+runs:
+  using: 'docker'
+  image: 'docker://ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724' # ratchet:docker://ubuntu:20.04
 `,
 		},
 		{
@@ -258,6 +586,9 @@ func TestUpgrade(t *testing.T) {
 			"actions://good/repo/sub/path@a12a3943": {
 				Resolved: "actions://good/repo/sub/path@v2.1.0",
 			},
+			"container://ubuntu:20.04": {
+				Resolved: "container://ubuntu:24.04",
+			},
 		},
 	)
 	if err != nil {
@@ -371,6 +702,25 @@ jobs:
       - uses: 'good/repo@v0' # ratchet:exclude # this is a comment
 		`,
 		},
+		{
+			name: "container",
+			in: `
+jobs:
+  my_job:
+    container:
+      image: 'ubuntu:20.04'
+    steps:
+      - uses: 'docker://ubuntu:20.04'
+`,
+			exp: `
+jobs:
+  my_job:
+    container:
+      image: 'ubuntu:24.04' # ratchet:ubuntu:24.04
+    steps:
+      - uses: 'docker://ubuntu:24.04' # ratchet:docker://ubuntu:24.04
+`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -442,6 +792,31 @@ func TestUnpin(t *testing.T) {
 - uses: "i/am@pinned" # comment
 `,
 		},
+		{
+			name: "frozen_comment",
+			in:   `uses: "my/repo@v0" # frozen: do not bump`,
+			exp:  `uses: "my/repo@v0" # frozen: do not bump`,
+		},
+		{
+			name: "frozen_comment_pinned",
+			in:   `uses: "my/repo@abcd1234" # frozen: do not bump ratchet:my/repo@v0`,
+			exp:  `uses: "my/repo@v0" # frozen: do not bump`,
+		},
+		{
+			name: "uses_comment_before",
+			in:   `uses: "my/repo@abcd1234" # this is a code comment ratchet:my/repo@v0`,
+			exp:  `uses: "my/repo@v0" # this is a code comment`,
+		},
+		{
+			name: "uses_comment_before_and_after",
+			in:   `uses: "my/repo@abcd1234" # this is ratchet:my/repo@v0 a code comment`,
+			exp:  `uses: "my/repo@v0" # this is a code comment`,
+		},
+		{
+			name: "empty_quotes_comment",
+			in:   `uses: "my/repo@abcd1234" # ratchet:""`,
+			exp:  `uses: "\"\""`,
+		},
 		{
 			name: "exclude_comment",
 			in:   `uses: "my/repo@v0" # ratchet:exclude more comment`,
@@ -459,7 +834,7 @@ func TestUnpin(t *testing.T) {
 				"test.yml": m,
 			}
 
-			if err := Unpin(ctx, nodes); err != nil {
+			if err := Unpin(ctx, new(Actions), nodes); err != nil {
 				t.Fatal(err)
 			}
 
@@ -568,6 +943,30 @@ func TestExtractOriginalFromComment(t *testing.T) {
 			extract: "foo/bar@v3",
 			rest:    "this is a code comment",
 		},
+		{
+			name:    "comment_before",
+			in:      "# this is a code comment ratchet:foo/bar@v3",
+			extract: "foo/bar@v3",
+			rest:    "# this is a code comment",
+		},
+		{
+			name:    "comment_before_and_after",
+			in:      "# this is ratchet:foo/bar@v3 a code comment",
+			extract: "foo/bar@v3",
+			rest:    "# this is a code comment",
+		},
+		{
+			name:    "comment_marker",
+			in:      "# ratchet:foo/bar@v3",
+			extract: "foo/bar@v3",
+			rest:    "",
+		},
+		{
+			name:    "frozen_is_not_ratchet",
+			in:      "# frozen: do not bump",
+			extract: "",
+			rest:    "# frozen: do not bump",
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+// frozenPrefix is the prefix pre-commit uses to record the original revision of
+// a frozen ref (e.g. "# frozen: v4.5.0").
+const frozenPrefix = "frozen: "
+
+type PreCommit struct {
+	// Frozen records the original revision in pre-commit's own style (e.g.
+	// "# frozen: v4.5.0"), like "pre-commit autoupdate --freeze", instead of a
+	// ratchet comment.
+	Frozen bool
+}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (p *PreCommit) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the pre-commit refs from the documents. It extracts the rev of
+// every repo hosted on GitHub as an actions-style ref, so it is pinned to a
+// commit SHA. The "local" and "meta" repos, and repos hosted elsewhere, are
+// ignored. Revs with pre-commit's frozen comment (e.g. "# frozen: v4.5.0") are
+// unpinned to the frozen revision.
+func (p *PreCommit) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := p.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (p *PreCommit) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		repos := mappingValue(docMap, "repos")
+		if repos == nil || repos.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, repo := range repos.Content {
+			url := mappingValue(repo, "repo")
+			rev := mappingValue(repo, "rev")
+			if url == nil || rev == nil || rev.Kind != yaml.ScalarNode || rev.Value == "" {
+				continue
+			}
+
+			// The "local" and "meta" repos do not have a rev, but skip them
+			// explicitly in case they do.
+			if url.Value == "local" || url.Value == "meta" {
+				continue
+			}
+
+			name := githubRepo(url.Value)
+			if name == "" {
+				continue
+			}
+
+			projection := versionProjection(name, "")
+			projection.Original = extractOriginalFromFrozenComment
+			if p.Frozen {
+				projection.Comment = frozenComment
+			}
+			refs.AddProjected(resolver.NormalizeActionsRef(projection.Ref(rev.Value)), rev, projection)
+		}
+	}
+
+	return nil
+}
+
+// frozenComment returns the comment in pre-commit's frozen style, which records
+// the original value at the start of the comment.
+func frozenComment(comment, pin string) string {
+	_, comment = extractOriginalFromFrozenComment(comment)
+	comment = strings.TrimSpace(strings.TrimPrefix(comment, "#"))
+
+	if comment == "" {
+		return frozenPrefix + pin
+	}
+
+	return frozenPrefix + pin + " " + comment
+}
+
+// extractOriginalFromFrozenComment pulls the original value from a comment that
+// starts with pre-commit's frozen prefix. Other comments are read as ratchet
+// comments.
+func extractOriginalFromFrozenComment(comment string) (string, string) {
+	trimmed := strings.TrimLeft(comment, "# ")
+	if !strings.HasPrefix(trimmed, frozenPrefix) {
+		return extractOriginalFromComment(comment)
+	}
+
+	original, rest, _ := strings.Cut(trimmed[len(frozenPrefix):], " ")
+	return original, rest
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+func TestPreCommit_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+repos:
+`,
+			exp: nil,
+		},
+		{
+			name: "repos",
+			in: `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.5.0
+    hooks:
+      - id: trailing-whitespace
+  - repo: https://github.com/psf/black.git
+    rev: 23.12.1
+    hooks:
+      - id: black
+  - repo: git@github.com:golangci/golangci-lint
+    rev: 2541b1294d2704b0964813337f33b291d3f8596b
+    hooks:
+      - id: golangci-lint
+`,
+			exp: []string{
+				"actions://golangci/golangci-lint@2541b1294d2704b0964813337f33b291d3f8596b",
+				"actions://pre-commit/pre-commit-hooks@v4.5.0",
+				"actions://psf/black@23.12.1",
+			},
+		},
+		{
+			name: "skips_local_meta_and_other_hosts",
+			in: `
+repos:
+  - repo: local
+    hooks:
+      - id: lint
+  - repo: meta
+    hooks:
+      - id: check-hooks-apply
+  - repo: https://gitlab.com/pycqa/flake8
+    rev: 3.9.2
+    hooks:
+      - id: flake8
+  - repo: https://github.com.example.com/foo/bar
+    rev: v1
+    hooks:
+      - id: bar
+`,
+			exp: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(PreCommit).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPreCommit_Pin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://pre-commit/pre-commit-hooks@v4.5.0": {
+			Resolved: "pre-commit/pre-commit-hooks@c4a0b883114b00d8d76b479c820ce7950211c99b",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		parser *PreCommit
+		in     string
+		exp    string
+	}{
+		{
+			name:   "ratchet",
+			parser: new(PreCommit),
+			in: `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.5.0
+`,
+			exp: `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: c4a0b883114b00d8d76b479c820ce7950211c99b # ratchet:v4.5.0
+`,
+		},
+		{
+			name:   "frozen",
+			parser: &PreCommit{Frozen: true},
+			in: `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.5.0 # hooks for everything
+`,
+			exp: `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: c4a0b883114b00d8d76b479c820ce7950211c99b # frozen: v4.5.0 hooks for everything
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := helperStringToYAML(t, tc.in)
+
+			if err := Pin(ctx, res, tc.parser, map[string]*yaml.Node{"test.yml": m}, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := helperYAMLToString(t, m), strings.TrimSpace(tc.exp); got != want {
+				t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+
+			// Frozen comments are unpinned even without the Frozen option.
+			if err := Unpin(ctx, new(PreCommit), map[string]*yaml.Node{"test.yml": m}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := helperYAMLToString(t, m), strings.TrimSpace(tc.in); got != want {
+				t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestPreCommit_FrozenOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://pre-commit/pre-commit-hooks@v4.5.0": {
+			Resolved: "pre-commit/pre-commit-hooks@c4a0b883114b00d8d76b479c820ce7950211c99b",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.5.0
+`
+	exp := `
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: c4a0b883114b00d8d76b479c820ce7950211c99b # frozen: v4.5.0
+`
+
+	// The option applies to the pre-commit parser, including when it is
+	// detected by the auto parser.
+	for _, name := range []string{"precommit", "auto"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			par, err := ForWithOptions(ctx, name, &Options{PreCommitFrozen: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := helperStringToYAML(t, in)
+			nodes := map[string]*yaml.Node{".pre-commit-config.yaml": m}
+
+			if err := Pin(ctx, res, par, nodes, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := helperYAMLToString(t, m), strings.TrimSpace(exp); got != want {
+				t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractOriginalFromFrozenComment(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		in      string
+		extract string
+		rest    string
+	}{
+		{
+			name:    "frozen",
+			in:      "# frozen: v4.5.0",
+			extract: "v4.5.0",
+			rest:    "",
+		},
+		{
+			name:    "frozen_comment",
+			in:      "frozen: v4.5.0 this is a code comment",
+			extract: "v4.5.0",
+			rest:    "this is a code comment",
+		},
+		{
+			name:    "frozen_not_first",
+			in:      "# this is not frozen: v4.5.0",
+			extract: "",
+			rest:    "# this is not frozen: v4.5.0",
+		},
+		{
+			name:    "ratchet",
+			in:      "# ratchet:v4.5.0 this is a code comment",
+			extract: "v4.5.0",
+			rest:    "this is a code comment",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			extracted, rest := extractOriginalFromFrozenComment(tc.in)
+
+			if got, want := extracted, tc.extract; got != want {
+				t.Errorf("expected extracted %q to be %q", got, want)
+			}
+
+			if got, want := rest, tc.rest; got != want {
+				t.Errorf("expected rest %q to be %q", got, want)
+			}
+		})
+	}
+}
@@ -9,11 +9,55 @@ import (
 	// Using banydonk/yaml instead of the default yaml pkg because the default
 	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
 	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 type RefsList struct {
-	once sync.Once
-	refs map[string][]*yaml.Node
+	once        sync.Once
+	refs        map[string][]*yaml.Node
+	projections map[*yaml.Node]*Projection
+}
+
+// Projection converts between denormalized refs and node values, for nodes
+// that only contain part of a ref (e.g. a version field next to a separate
+// repository field).
+type Projection struct {
+	// Value returns the node value for the given denormalized ref.
+	Value func(ref string) string
+
+	// Ref returns the denormalized ref for the given node value.
+	Ref func(value string) string
+
+	// Comment optionally returns the line comment that records the original
+	// value of the node. By default, the value is recorded in a ratchet comment.
+	Comment func(comment, original string) string
+
+	// Original optionally returns the original value recorded in the line
+	// comment, and the rest of the comment. It is the inverse of Comment. By
+	// default, the value is read from a ratchet comment.
+	Original func(comment string) (string, string)
+
+	// Unpinned optionally returns the node value for the original value read
+	// from the line comment. By default, the original value is used as-is.
+	Unpinned func(original string) string
+}
+
+// versionProjection returns a projection for nodes that contain only the
+// version of the ref "name@version", optionally with a prefix that is kept for
+// versions that are not absolute (e.g. "refs/tags/").
+func versionProjection(name, prefix string) *Projection {
+	return &Projection{
+		Value: func(ref string) string {
+			_, version, _ := strings.Cut(ref, "@")
+			if isAbsolute(version) {
+				return version
+			}
+			return prefix + version
+		},
+		Ref: func(value string) string {
+			return name + "@" + strings.TrimPrefix(value, prefix)
+		},
+	}
 }
 
 func (l *RefsList) Add(ref string, m *yaml.Node) {
@@ -21,6 +65,61 @@ func (l *RefsList) Add(ref string, m *yaml.Node) {
 	l.refs[ref] = append(l.refs[ref], m)
 }
 
+// AddProjected adds the ref for a node that contains only part of the ref. The
+// projection is used to update the node when the ref is pinned or upgraded.
+func (l *RefsList) AddProjected(ref string, m *yaml.Node, p *Projection) {
+	l.Add(ref, m)
+	l.projections[m] = p
+}
+
+// Projection returns the projection for the node, or nil if the node contains
+// the entire ref.
+func (l *RefsList) Projection(m *yaml.Node) *Projection {
+	l.once.Do(l.init)
+	return l.projections[m]
+}
+
+// Merge adds all refs and projections from the other list.
+func (l *RefsList) Merge(other *RefsList) {
+	l.once.Do(l.init)
+	for ref, nodes := range other.All() {
+		for _, node := range nodes {
+			if p := other.Projection(node); p != nil {
+				l.AddProjected(ref, node, p)
+			} else {
+				l.Add(ref, node)
+			}
+		}
+	}
+}
+
+// replace replaces the denormalized ref in the node value with the new
+// denormalized ref, using the projection for the node if there is one.
+func (l *RefsList) replace(node *yaml.Node, oldRef, newRef string) string {
+	if p := l.Projection(node); p != nil {
+		return p.Value(newRef)
+	}
+	return strings.Replace(node.Value, oldRef, newRef, 1)
+}
+
+// annotate returns the line comment for the node, recording the given original
+// value.
+func (l *RefsList) annotate(node *yaml.Node, original string) string {
+	if p := l.Projection(node); p != nil && p.Comment != nil {
+		return p.Comment(node.LineComment, original)
+	}
+	return appendOriginalToComment(node.LineComment, original)
+}
+
+// original returns the original value recorded in the line comment of the node,
+// and the rest of the comment, using the node's projection if it has one.
+func (l *RefsList) original(node *yaml.Node) (string, string) {
+	if p := l.Projection(node); p != nil && p.Original != nil {
+		return p.Original(node.LineComment)
+	}
+	return extractOriginalFromComment(node.LineComment)
+}
+
 func (l *RefsList) Refs() []string {
 	l.once.Do(l.init)
 	return slices.Sorted(maps.Keys(l.refs))
@@ -35,6 +134,9 @@ func (l *RefsList) init() {
 	if l.refs == nil {
 		l.refs = make(map[string][]*yaml.Node)
 	}
+	if l.projections == nil {
+		l.projections = make(map[*yaml.Node]*Projection)
+	}
 }
 
 // isAbsolute returns true if the given reference is absolute, or false
@@ -44,7 +146,14 @@ func (l *RefsList) init() {
 // characters. GitHub actually forbids this format for branch names.
 //
 // A container ref is absolute if it's a sha256 with a hex digest.
+//
+// An orb ref is absolute if it's an exact version, since published orb
+// versions are immutable.
 func isAbsolute(ref string) bool {
+	if rest, ok := strings.CutPrefix(ref, resolver.OrbProtocol); ok {
+		return resolver.IsExactOrbVersion(rest)
+	}
+
 	parts := strings.Split(ref, "@")
 	last := parts[len(parts)-1]
 
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	// Using banydonk/yaml instead of the default yaml pkg because the default
 	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
@@ -16,7 +17,9 @@ func (t *Tekton) DenormalizeRef(ref string) string {
 	return resolver.DenormalizeRef(ref)
 }
 
-// Parse pulls the Tekton Ci refs from the documents.
+// Parse pulls the Tekton Ci refs from the documents. It extracts step images,
+// OCI bundles (from taskRef and pipelineRef, or the "bundles" resolver), and
+// the revision of the "git" resolver for repositories on GitHub.
 func (t *Tekton) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
 	var refs RefsList
 	for pth, node := range nodes {
@@ -58,20 +61,88 @@ func (d *Tekton) findSpecs(refs *RefsList, node *yaml.Node) {
 	for i, specsMap := range node.Content {
 		if specsMap.Value == "spec" {
 			specs := node.Content[i+1]
-			d.findImages(refs, specs)
+			d.findRefs(refs, specs)
 		}
 	}
 }
 
-func (d *Tekton) findImages(refs *RefsList, node *yaml.Node) {
-	for i, property := range node.Content {
-		if property.Value == "image" {
-			image := node.Content[i+1]
-			ref := resolver.NormalizeContainerRef(image.Value)
-			refs.Add(ref, image)
-			break
-		} else {
-			d.findImages(refs, property)
+// findRefs recursively finds the container images, OCI bundles, and remote
+// resolver references in the node.
+func (d *Tekton) findRefs(refs *RefsList, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			// Step images, and the deprecated "bundle" field of taskRef and
+			// pipelineRef, should be resolved as Docker references.
+			if (key.Value == "image" || key.Value == "bundle") && value.Kind == yaml.ScalarNode {
+				ref := resolver.NormalizeContainerRef(value.Value)
+				refs.Add(ref, value)
+				continue
+			}
+
+			d.findRefs(refs, value)
+		}
+
+		if res := mappingValue(node, "resolver"); res != nil && res.Kind == yaml.ScalarNode {
+			d.findResolverRefs(refs, res.Value, mappingValue(node, "params"))
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			d.findRefs(refs, item)
+		}
+	}
+}
+
+// findResolverRefs adds the references in the params of a remote resolver. The
+// "bundles" resolver's bundle is resolved as a Docker reference, and the "git"
+// resolver's revision is resolved to a commit SHA for repositories on GitHub.
+// Params that use substitutions (e.g. "$(params.revision)") are ignored.
+func (d *Tekton) findResolverRefs(refs *RefsList, name string, params *yaml.Node) {
+	if params == nil || params.Kind != yaml.SequenceNode {
+		return
+	}
+
+	values := make(map[string]*yaml.Node, len(params.Content))
+	for _, param := range params.Content {
+		key, value := mappingValue(param, "name"), mappingValue(param, "value")
+		if key == nil || value == nil || value.Kind != yaml.ScalarNode ||
+			value.Value == "" || strings.Contains(value.Value, "$(") {
+			continue
+		}
+		values[key.Value] = value
+	}
+
+	switch name {
+	case "bundles":
+		if bundle := values["bundle"]; bundle != nil {
+			ref := resolver.NormalizeContainerRef(bundle.Value)
+			refs.Add(ref, bundle)
+		}
+	case "git":
+		revision := values["revision"]
+		if revision == nil {
+			return
 		}
+
+		// The repository is either a URL, or an org and repo on an SCM
+		// provider. Without a revision, the default branch is used.
+		var repo string
+		if url := values["url"]; url != nil {
+			repo = githubRepo(url.Value)
+		} else if org, name := values["org"], values["repo"]; org != nil && name != nil {
+			scmType, serverURL := values["scmType"], values["serverURL"]
+			if (scmType == nil || scmType.Value == "github") &&
+				(serverURL == nil || strings.TrimSuffix(serverURL.Value, "/") == "https://github.com") {
+				repo = org.Value + "/" + name.Value
+			}
+		}
+		if repo == "" {
+			return
+		}
+
+		projection := versionProjection(repo, "")
+		refs.AddProjected(resolver.NormalizeActionsRef(projection.Ref(revision.Value)), revision, projection)
 	}
 }
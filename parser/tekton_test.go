@@ -1,10 +1,13 @@
 package parser
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
 )
 
 func TestTekton_Parse(t *testing.T) {
@@ -41,6 +44,124 @@ spec:
 				"container://alpine/git",
 			},
 		},
+		{
+			name: "image_and_nested_steps",
+			in: `
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: build
+spec:
+  stepTemplate:
+    image: ubuntu:24.04
+    env:
+      - name: HOME
+        value: /tekton/home
+  steps:
+    - image: alpine:3.20
+      name: first
+    - image: golang:1.24
+      name: second
+  sidecars:
+    - image: redis:7
+`,
+			exp: []string{
+				"container://alpine:3.20",
+				"container://golang:1.24",
+				"container://redis:7",
+				"container://ubuntu:24.04",
+			},
+		},
+		{
+			name: "bundles",
+			in: `
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: release
+spec:
+  tasks:
+    - name: deprecated
+      taskRef:
+        name: build
+        bundle: gcr.io/my-project/build-task:v1
+    - name: resolver
+      taskRef:
+        resolver: bundles
+        params:
+          - name: bundle
+            value: gcr.io/my-project/test-task:v2
+          - name: name
+            value: test
+          - name: kind
+            value: task
+    - name: substitution
+      taskRef:
+        resolver: bundles
+        params:
+          - name: bundle
+            value: $(params.bundle)
+          - name: name
+            value: test
+`,
+			exp: []string{
+				"container://gcr.io/my-project/build-task:v1",
+				"container://gcr.io/my-project/test-task:v2",
+			},
+		},
+		{
+			name: "git",
+			in: `
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: release
+spec:
+  tasks:
+    - name: url
+      taskRef:
+        resolver: git
+        params:
+          - name: url
+            value: https://github.com/tektoncd/catalog.git
+          - name: revision
+            value: main
+          - name: pathInRepo
+            value: task/git-clone/0.9/git-clone.yaml
+    - name: api
+      taskRef:
+        resolver: git
+        params:
+          - name: org
+            value: my-org
+          - name: repo
+            value: tasks
+          - name: revision
+            value: v1.2.0
+          - name: pathInRepo
+            value: lint.yaml
+    - name: other_host
+      taskRef:
+        resolver: git
+        params:
+          - name: url
+            value: https://gitlab.com/my-org/tasks.git
+          - name: revision
+            value: main
+    - name: default_branch
+      taskRef:
+        resolver: git
+        params:
+          - name: url
+            value: https://github.com/my-org/tasks.git
+          - name: pathInRepo
+            value: lint.yaml
+`,
+			exp: []string{
+				"actions://my-org/tasks@v1.2.0",
+				"actions://tektoncd/catalog@main",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -62,3 +183,84 @@ spec:
 		})
 	}
 }
+
+func TestTekton_Pin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	res, err := resolver.NewTest(map[string]*resolver.TestResult{
+		"actions://tektoncd/catalog@main": {
+			Resolved: "tektoncd/catalog@c4a0b883114b00d8d76b479c820ce7950211c99b",
+		},
+		"container://gcr.io/my-project/test-task:v2": {
+			Resolved: "gcr.io/my-project/test-task@sha256:ab1dc7a2e5a3fbc39e3e6e3b3a7bb2b3eb86d17adda1a16c7d3a8b95b7bbc2e8",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := `
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: release
+spec:
+  tasks:
+    - name: catalog
+      taskRef:
+        resolver: git
+        params:
+          - name: url
+            value: https://github.com/tektoncd/catalog.git
+          - name: revision
+            value: main
+    - name: test
+      taskRef:
+        resolver: bundles
+        params:
+          - name: bundle
+            value: gcr.io/my-project/test-task:v2
+`
+	exp := `
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: release
+spec:
+  tasks:
+    - name: catalog
+      taskRef:
+        resolver: git
+        params:
+          - name: url
+            value: https://github.com/tektoncd/catalog.git
+          - name: revision
+            value: c4a0b883114b00d8d76b479c820ce7950211c99b # ratchet:main
+    - name: test
+      taskRef:
+        resolver: bundles
+        params:
+          - name: bundle
+            value: gcr.io/my-project/test-task@sha256:ab1dc7a2e5a3fbc39e3e6e3b3a7bb2b3eb86d17adda1a16c7d3a8b95b7bbc2e8 # ratchet:gcr.io/my-project/test-task:v2
+`
+
+	m := helperStringToYAML(t, in)
+
+	if err := Pin(ctx, res, new(Tekton), map[string]*yaml.Node{"test.yml": m}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(exp); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+
+	if err := Unpin(ctx, new(Tekton), map[string]*yaml.Node{"test.yml": m}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := helperYAMLToString(t, m), strings.TrimSpace(in); got != want {
+		t.Errorf("expected \n\n%s\n\nto be\n\n%s\n\n", got, want)
+	}
+}
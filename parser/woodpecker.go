@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"fmt"
+
+	// Using banydonk/yaml instead of the default yaml pkg because the default
+	// pkg incorrectly escapes unicode. https://github.com/go-yaml/yaml/issues/737
+	"github.com/braydonk/yaml"
+	"github.com/sethvargo/ratchet/resolver"
+)
+
+type Woodpecker struct{}
+
+// DenormalizeRef changes the resolved ref into a ref that the parser expects.
+func (w *Woodpecker) DenormalizeRef(ref string) string {
+	return resolver.DenormalizeRef(ref)
+}
+
+// Parse pulls the Woodpecker CI refs from the documents. It extracts the images
+// of steps, services, and clone steps, which are either maps of name to step or
+// lists of steps. It does not support references with variables.
+func (w *Woodpecker) Parse(nodes map[string]*yaml.Node) (*RefsList, error) {
+	var refs RefsList
+
+	for pth, node := range nodes {
+		if err := w.parseOne(&refs, node); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+	}
+
+	return &refs, nil
+}
+
+func (w *Woodpecker) parseOne(refs *RefsList, node *yaml.Node) error {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind != yaml.DocumentNode {
+		return fmt.Errorf("expected document node, got %v", node.Kind)
+	}
+
+	for _, docMap := range node.Content {
+		if docMap.Kind != yaml.MappingNode {
+			continue
+		}
+
+		// "pipeline" is the legacy name for "steps".
+		for _, key := range []string{"steps", "pipeline", "services", "clone"} {
+			section := mappingValue(docMap, key)
+			if section == nil {
+				continue
+			}
+
+			var steps []*yaml.Node
+			switch section.Kind {
+			case yaml.MappingNode:
+				for i := 1; i < len(section.Content); i += 2 {
+					steps = append(steps, section.Content[i])
+				}
+			case yaml.SequenceNode:
+				steps = section.Content
+			}
+
+			for _, step := range steps {
+				if image := mappingValue(step, "image"); image != nil && image.Kind == yaml.ScalarNode {
+					ref := resolver.NormalizeContainerRef(image.Value)
+					refs.Add(ref, image)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braydonk/yaml"
+)
+
+func TestWoodpecker_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  []string
+	}{
+		{
+			name: "mostly_empty_file",
+			in: `
+steps:
+`,
+			exp: nil,
+		},
+		{
+			name: "steps_map",
+			in: `
+clone:
+  git:
+    image: woodpeckerci/plugin-git:2
+steps:
+  build:
+    image: golang:1.12
+    commands:
+      - go build
+  publish:
+    image: woodpeckerci/plugin-docker-buildx
+services:
+  database:
+    image: mysql:8
+`,
+			exp: []string{
+				"container://golang:1.12",
+				"container://mysql:8",
+				"container://woodpeckerci/plugin-docker-buildx",
+				"container://woodpeckerci/plugin-git:2",
+			},
+		},
+		{
+			name: "steps_list",
+			in: `
+steps:
+  - name: build
+    image: golang:1.12
+  - name: test
+    image: golang:1.12
+services:
+  - name: database
+    image: mysql:8
+`,
+			exp: []string{
+				"container://golang:1.12",
+				"container://mysql:8",
+			},
+		},
+		{
+			name: "legacy_pipeline",
+			in: `
+pipeline:
+  build:
+    image: golang:1.12
+`,
+			exp: []string{
+				"container://golang:1.12",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes := map[string]*yaml.Node{
+				"test.yml": helperStringToYAML(t, tc.in),
+			}
+
+			refs, err := new(Woodpecker).Parse(nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := refs.Refs(), tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
@@ -84,6 +84,11 @@ func (g *Actions) LatestVersion(ctx context.Context, value string) (string, erro
 	ref := githubRef.ref
 	branchRef := "heads/" + ref
 
+	// HEAD is the default branch, so do not upgrade it either.
+	if ref == "HEAD" {
+		return value, nil
+	}
+
 	// Fetching the Git Ref allows us to determine if the ref is for a branch
 	// or tag. We must explicitly format for either `tags/` or `heads/`
 	// (branches). We arbitrarily check if the ref is for a branch, therefore
@@ -107,20 +112,183 @@ func (g *Actions) LatestVersion(ctx context.Context, value string) (string, erro
 	if path != "" {
 		name = name + "/" + path
 	}
-	version := *release.TagName
-	if strings.HasPrefix(ref, "v") {
-		refPrecision := strings.Count(githubRef.ref, ".")
-		for strings.Count(version, ".") < refPrecision {
-			version += ".0"
-		}
-		versionParts := strings.Split(version, ".")
-		version = strings.Join(versionParts[:refPrecision+1], ".")
-	}
+	version := matchVersionPrecision(ref, *release.TagName)
 
 	result := fmt.Sprintf("%s@%s", name, version)
 	return result, nil
 }
 
+// matchVersionPrecision truncates or pads the version to the same number of
+// components as the ref (e.g. "v1" and "v2.3.4" is "v2"), so floating version
+// tags stay floating. Refs that do not look like versions are unchanged.
+func matchVersionPrecision(ref, version string) string {
+	if !strings.HasPrefix(ref, "v") {
+		return version
+	}
+
+	refPrecision := strings.Count(ref, ".")
+	for strings.Count(version, ".") < refPrecision {
+		version += ".0"
+	}
+	versionParts := strings.Split(version, ".")
+	return strings.Join(versionParts[:refPrecision+1], ".")
+}
+
+// VerifyCommit reports whether the commit pinned in the given reference (e.g.
+// "actions/checkout@<sha>") is reachable from the default branch of the named
+// repository, or from a branch or tag that matches the given version (e.g. "v4"
+// matches "v4" and "v4.1.0"). The version is usually the one recorded in the
+// ratchet comment, and may be empty. GitHub serves commits from forks under the
+// parent repository's name, so a commit that is not reachable may be an
+// impostor from a fork.
+//
+// Only the matching branches and tags are compared, so the cost is one request
+// for the repository, two for the matching refs, and one comparison per
+// candidate, instead of one per branch and tag in the repository.
+func (g *Actions) VerifyCommit(ctx context.Context, value, version string) (bool, error) {
+	githubRef, err := ParseActionRef(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse github ref: %w", err)
+	}
+	owner := githubRef.owner
+	repo := githubRef.repo
+	sha := strings.ToLower(githubRef.ref)
+
+	repository, resp, err := g.client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	// Check the default branch first, since that is where most commits live.
+	defaultBranch := repository.GetDefaultBranch()
+	candidates := make([]string, 0, 4)
+	if defaultBranch != "" {
+		candidates = append(candidates, defaultBranch)
+	}
+
+	if version != "" {
+		heads, err := g.matchingHeads(ctx, owner, repo, version)
+		if err != nil {
+			return false, err
+		}
+
+		// The fast path is a commit at the head of a matching branch or tag,
+		// which is the common case for freshly-pinned references.
+		for _, head := range heads {
+			if strings.EqualFold(head.sha, sha) {
+				return true, nil
+			}
+		}
+
+		for _, head := range heads {
+			if head.name != defaultBranch {
+				candidates = append(candidates, head.name)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		comparison, resp, err := g.client.Repositories.CompareCommits(ctx, owner, repo, candidate, sha,
+			&github.ListOptions{PerPage: 1})
+		if err != nil {
+			// The commit does not exist in the repository network at all.
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to compare %s to %s: %w", candidate, sha, err)
+		}
+
+		switch comparison.GetStatus() {
+		case "behind", "identical":
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IsAncestor reports whether the commit pinned in ancestor (e.g.
+// "actions/checkout@<sha>") is an ancestor of, or the same as, the commit
+// pinned in ref. Both references must be in the same repository.
+func (g *Actions) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	ancestorRef, err := ParseActionRef(ancestor)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse github ref: %w", err)
+	}
+
+	githubRef, err := ParseActionRef(ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse github ref: %w", err)
+	}
+
+	owner := githubRef.owner
+	repo := githubRef.repo
+	if !strings.EqualFold(ancestorRef.owner, owner) || !strings.EqualFold(ancestorRef.repo, repo) {
+		return false, nil
+	}
+
+	comparison, resp, err := g.client.Repositories.CompareCommits(ctx, owner, repo,
+		ancestorRef.ref, githubRef.ref, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare %s to %s: %w", ancestorRef.ref, githubRef.ref, err)
+	}
+
+	switch comparison.GetStatus() {
+	case "ahead", "identical":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// gitHead is the name and commit of a branch or tag.
+type gitHead struct {
+	name string
+	sha  string
+}
+
+// matchingHeads returns the tags and branches in the repository that are named
+// version, or that start with version followed by a dot (e.g. "v4" matches
+// "v4.1.0" but not "v40"). Tags are returned before branches.
+func (g *Actions) matchingHeads(ctx context.Context, owner, repo, version string) ([]*gitHead, error) {
+	var heads []*gitHead
+
+	for _, prefix := range []string{"tags/", "heads/"} {
+		opts := &github.ReferenceListOptions{
+			Ref:         prefix + version,
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			refs, resp, err := g.client.Git.ListMatchingRefs(ctx, owner, repo, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list matching refs: %w", err)
+			}
+			for _, ref := range refs {
+				name := strings.TrimPrefix(ref.GetRef(), "refs/"+prefix)
+				if name != version && !strings.HasPrefix(name, version+".") {
+					continue
+				}
+				heads = append(heads, &gitHead{
+					name: name,
+					sha:  ref.GetObject().GetSHA(),
+				})
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	return heads, nil
+}
+
 func ParseActionRef(s string) (*GitHubRef, error) {
 	parts := strings.SplitN(s, "/", 2)
 	if len(parts) < 2 {
@@ -2,10 +2,16 @@ package resolver
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/google/go-github/v73/github"
 )
 
 func TestActions_Resolve(t *testing.T) {
@@ -131,6 +137,231 @@ func TestActions_LatestVersion(t *testing.T) {
 	}
 }
 
+func TestActions_VerifyCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	const (
+		headSHA     = "1111111111111111111111111111111111111111"
+		tagSHA      = "2222222222222222222222222222222222222222"
+		ancestorSHA = "3333333333333333333333333333333333333333"
+		impostorSHA = "4444444444444444444444444444444444444444"
+		missingSHA  = "5555555555555555555555555555555555555555"
+		releaseSHA  = "6666666666666666666666666666666666666666"
+		backportSHA = "7777777777777777777777777777777777777777"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/foo/bar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"bar","default_branch":"main"}`)
+	})
+	mux.HandleFunc("GET /repos/foo/bar/git/matching-refs/{ref...}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("ref") {
+		case "tags/v1":
+			fmt.Fprintf(w, `[
+				{"ref":"refs/tags/v1","object":{"type":"commit","sha":%q}},
+				{"ref":"refs/tags/v1.0.0","object":{"type":"commit","sha":%q}},
+				{"ref":"refs/tags/v10","object":{"type":"commit","sha":%q}}
+			]`, tagSHA, releaseSHA, impostorSHA)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+	mux.HandleFunc("GET /repos/foo/bar/compare/{basehead}", func(w http.ResponseWriter, r *http.Request) {
+		base, head, _ := strings.Cut(r.PathValue("basehead"), "...")
+		switch {
+		case head == headSHA && base == "main":
+			fmt.Fprint(w, `{"status":"identical"}`)
+		case head == ancestorSHA && base == "main":
+			fmt.Fprint(w, `{"status":"behind"}`)
+		case head == backportSHA && base == "v1.0.0":
+			fmt.Fprint(w, `{"status":"behind"}`)
+		case head == missingSHA:
+			http.NotFound(w, r)
+		default:
+			fmt.Fprint(w, `{"status":"diverged"}`)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = baseURL
+
+	resolver := &Actions{client: client}
+
+	cases := []struct {
+		name    string
+		in      string
+		version string
+		exp     bool
+	}{
+		{
+			name: "branch_head",
+			in:   "foo/bar@" + headSHA,
+			exp:  true,
+		},
+		{
+			name:    "tag",
+			in:      "foo/bar/path@" + tagSHA,
+			version: "v1",
+			exp:     true,
+		},
+		{
+			name: "ancestor",
+			in:   "foo/bar@" + ancestorSHA,
+			exp:  true,
+		},
+		{
+			name:    "ancestor_of_matching_tag",
+			in:      "foo/bar@" + backportSHA,
+			version: "v1",
+			exp:     true,
+		},
+		{
+			name: "tag_without_version",
+			in:   "foo/bar@" + backportSHA,
+			exp:  false,
+		},
+		{
+			name:    "impostor",
+			in:      "foo/bar@" + impostorSHA,
+			version: "v1",
+			exp:     false,
+		},
+		{
+			name: "missing_commit",
+			in:   "foo/bar@" + missingSHA,
+			exp:  false,
+		},
+		{
+			name: "missing_repo",
+			in:   "foo/baz@" + headSHA,
+			exp:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.VerifyCommit(ctx, tc.in, tc.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+func TestActions_IsAncestor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	const (
+		oldSHA       = "1111111111111111111111111111111111111111"
+		newSHA       = "2222222222222222222222222222222222222222"
+		unrelatedSHA = "3333333333333333333333333333333333333333"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/foo/bar/compare/{basehead}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("basehead") {
+		case oldSHA + "..." + newSHA:
+			fmt.Fprint(w, `{"status":"ahead"}`)
+		case newSHA + "..." + newSHA:
+			fmt.Fprint(w, `{"status":"identical"}`)
+		case newSHA + "..." + oldSHA:
+			fmt.Fprint(w, `{"status":"behind"}`)
+		case unrelatedSHA + "..." + newSHA:
+			fmt.Fprint(w, `{"status":"diverged"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = baseURL
+
+	resolver := &Actions{client: client}
+
+	cases := []struct {
+		name     string
+		ancestor string
+		ref      string
+		exp      bool
+	}{
+		{
+			name:     "ancestor",
+			ancestor: "foo/bar@" + oldSHA,
+			ref:      "foo/bar/path@" + newSHA,
+			exp:      true,
+		},
+		{
+			name:     "identical",
+			ancestor: "foo/bar@" + newSHA,
+			ref:      "foo/bar@" + newSHA,
+			exp:      true,
+		},
+		{
+			name:     "descendant",
+			ancestor: "foo/bar@" + newSHA,
+			ref:      "foo/bar@" + oldSHA,
+			exp:      false,
+		},
+		{
+			name:     "diverged",
+			ancestor: "foo/bar@" + unrelatedSHA,
+			ref:      "foo/bar@" + newSHA,
+			exp:      false,
+		},
+		{
+			name:     "missing",
+			ancestor: "foo/bar@" + unrelatedSHA,
+			ref:      "foo/bar@" + oldSHA,
+			exp:      false,
+		},
+		{
+			name:     "different_repo",
+			ancestor: "foo/baz@" + oldSHA,
+			ref:      "foo/bar@" + newSHA,
+			exp:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.IsAncestor(ctx, tc.ancestor, tc.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
 func TestParseActionRef(t *testing.T) {
 	t.Parallel()
 
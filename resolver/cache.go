@@ -0,0 +1,150 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is the default amount of time a cached resolution is
+// considered valid.
+const DefaultCacheTTL = 1 * time.Hour
+
+// DefaultCacheDir returns the default directory for the on-disk resolution
+// cache. On most systems, this is inside $XDG_CACHE_HOME.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "ratchet"), nil
+}
+
+// Cache is a resolver that wraps another resolver and caches the results on
+// disk. Entries older than the TTL are resolved again.
+type Cache struct {
+	resolver Resolver
+	dir      string
+	ttl      time.Duration
+}
+
+// cacheEntry is the on-disk representation of a cached resolution.
+type cacheEntry struct {
+	Ref       string    `json:"ref"`
+	Resolved  string    `json:"resolved"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewCache creates a new caching resolver that stores entries in dir. If ttl
+// is less than or equal to zero, entries never expire.
+func NewCache(res Resolver, dir string, ttl time.Duration) (*Cache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("missing cache directory")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &Cache{
+		resolver: res,
+		dir:      dir,
+		ttl:      ttl,
+	}, nil
+}
+
+// Resolve resolves the ref, returning a cached value if one exists.
+func (c *Cache) Resolve(ctx context.Context, ref string) (string, error) {
+	return c.cached(ctx, "resolve", ref, c.resolver.Resolve)
+}
+
+// LatestVersion upgrades the ref, returning a cached value if one exists.
+func (c *Cache) LatestVersion(ctx context.Context, ref string) (string, error) {
+	return c.cached(ctx, "latest", ref, c.resolver.LatestVersion)
+}
+
+// cached looks up the ref in the cache for the given kind of operation. On a
+// miss, it calls fn and stores the result. Failures to write the cache are not
+// returned, since the cache is an optimization and the resolution succeeded.
+func (c *Cache) cached(ctx context.Context, kind, ref string, fn func(context.Context, string) (string, error)) (string, error) {
+	pth := c.path(kind, ref)
+
+	if entry := c.read(pth); entry != nil && entry.Ref == ref {
+		if c.ttl <= 0 || time.Since(entry.CreatedAt) < c.ttl {
+			return entry.Resolved, nil
+		}
+	}
+
+	resolved, err := fn(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	_ = c.write(pth, &cacheEntry{
+		Ref:       ref,
+		Resolved:  resolved,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	return resolved, nil
+}
+
+// path returns the path on disk for the cache entry.
+func (c *Cache) path(kind, ref string) string {
+	sum := sha256.Sum256([]byte(ref))
+	return filepath.Join(c.dir, kind, hex.EncodeToString(sum[:])+".json")
+}
+
+// read reads the cache entry at the given path, returning nil if the entry
+// does not exist or is invalid.
+func (c *Cache) read(pth string) *cacheEntry {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// write writes the cache entry to the given path. The write happens in a
+// temporary file that is renamed into place, so concurrent readers never see a
+// partial entry.
+func (c *Cache) write(pth string, entry *cacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	parent := filepath.Dir(pth)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(parent, "ratchet-")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), pth); err != nil {
+		return fmt.Errorf("failed to save cache file: %w", err)
+	}
+	return nil
+}
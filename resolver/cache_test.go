@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingResolver struct {
+	Resolver
+	calls atomic.Int64
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, value string) (string, error) {
+	r.calls.Add(1)
+	return r.Resolver.Resolve(ctx, value)
+}
+
+func (r *countingResolver) LatestVersion(ctx context.Context, value string) (string, error) {
+	r.calls.Add(1)
+	return r.Resolver.LatestVersion(ctx, value)
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	test, err := NewTest(map[string]*TestResult{
+		"actions://good/repo@v0": {
+			Resolved: "good/repo@a12a3943",
+		},
+	}, map[string]*TestResult{
+		"actions://good/repo@v0": {
+			Resolved: "actions://good/repo@v1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		ttl   time.Duration
+		calls int64
+	}{
+		{
+			name:  "cached",
+			ttl:   time.Hour,
+			calls: 2,
+		},
+		{
+			name:  "never_expires",
+			ttl:   0,
+			calls: 2,
+		},
+		{
+			name:  "expired",
+			ttl:   time.Nanosecond,
+			calls: 6,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &countingResolver{Resolver: test}
+			cache, err := NewCache(counter, t.TempDir(), tc.ttl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for range 3 {
+				resolved, err := cache.Resolve(ctx, "actions://good/repo@v0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := resolved, "good/repo@a12a3943"; got != want {
+					t.Errorf("expected %q to be %q", got, want)
+				}
+
+				latest, err := cache.LatestVersion(ctx, "actions://good/repo@v0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := latest, "actions://good/repo@v1"; got != want {
+					t.Errorf("expected %q to be %q", got, want)
+				}
+
+				// Ensure the expired case is actually expired.
+				time.Sleep(time.Millisecond)
+			}
+
+			if got, want := counter.calls.Load(), tc.calls; got != want {
+				t.Errorf("expected %d calls to be %d", got, want)
+			}
+		})
+	}
+}
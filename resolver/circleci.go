@@ -0,0 +1,303 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	CircleCIBaseURL = coalesce(os.Getenv("CIRCLECI_BASE_URL"), "https://circleci.com")
+	CircleCIToken   = os.Getenv("CIRCLECI_TOKEN")
+)
+
+// orbVersionsQuery lists the published versions of an orb. The API returns the
+// most recent versions first.
+const orbVersionsQuery = `query OrbVersions($name: String!, $count: Int!) {
+  orb(name: $name) {
+    versions(count: $count) {
+      version
+    }
+  }
+}`
+
+// orbVersionsCount is the maximum number of versions to consider when
+// resolving a floating version. The API does not support pagination, so older
+// versions cannot be listed.
+const orbVersionsCount = 200
+
+func NormalizeOrbRef(in string) string {
+	return OrbProtocol + in
+}
+
+// CircleCI resolves CircleCI orb references (e.g. "circleci/node@5.1") to the
+// exact published version (e.g. "circleci/node@5.1.0"). Published orb versions
+// are immutable, so the exact version is absolute.
+type CircleCI struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewCircleCI creates a new resolver for CircleCI orbs, using the host and
+// token from the environment.
+func NewCircleCI(ctx context.Context) (*CircleCI, error) {
+	return NewCircleCIWithURL(ctx, CircleCIBaseURL, CircleCIToken)
+}
+
+// NewCircleCIWithURL creates a new resolver for CircleCI orbs for the given
+// host. The token is only required for private orbs.
+func NewCircleCIWithURL(ctx context.Context, baseURL, token string) (*CircleCI, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid circleci base url %q", baseURL)
+	}
+
+	return &CircleCI{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}, nil
+}
+
+// Resolve resolves the orb version to the most recent published version that
+// matches it. "5" matches any 5.x.y version, "5.1" matches any 5.1.y version,
+// and "volatile" matches any version.
+func (c *CircleCI) Resolve(ctx context.Context, value string) (string, error) {
+	orbRef, err := ParseOrbRef(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse orb ref: %w", err)
+	}
+
+	versions, truncated, err := c.versions(ctx, orbRef.name)
+	if err != nil {
+		return "", err
+	}
+
+	var prefix []int
+	if orbRef.version != "volatile" {
+		var ok bool
+		prefix, ok = parseNumericVersion(orbRef.version)
+		if !ok {
+			return "", fmt.Errorf("invalid orb version %q", orbRef.version)
+		}
+	}
+
+	for _, version := range versions {
+		if slices.Equal(version[:len(prefix)], prefix) {
+			return orbRef.name + "@" + formatOrbVersion(version), nil
+		}
+	}
+	if truncated {
+		return "", fmt.Errorf("no version of %s matches %q in the %d most recent published versions",
+			orbRef.name, orbRef.version, orbVersionsCount)
+	}
+	return "", fmt.Errorf("no published version of %s matches %q", orbRef.name, orbRef.version)
+}
+
+// LatestVersion returns the most recent published version of the orb, with the
+// same precision as the given version, so floating versions stay floating.
+// "volatile" is not changed.
+func (c *CircleCI) LatestVersion(ctx context.Context, value string) (string, error) {
+	orbRef, err := ParseOrbRef(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse orb ref: %w", err)
+	}
+
+	if orbRef.version == "volatile" {
+		return value, nil
+	}
+
+	current, ok := parseNumericVersion(orbRef.version)
+	if !ok {
+		return "", fmt.Errorf("invalid orb version %q", orbRef.version)
+	}
+
+	versions, _, err := c.versions(ctx, orbRef.name)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no published versions of %s", orbRef.name)
+	}
+
+	return orbRef.name + "@" + formatOrbVersion(versions[0][:len(current)]), nil
+}
+
+// IsAncestor reports whether the orb version in ancestor is older than, or the
+// same as, the version in ref. Both references must be to the same orb.
+func (c *CircleCI) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	ancestorRef, err := ParseOrbRef(ancestor)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse orb ref: %w", err)
+	}
+
+	orbRef, err := ParseOrbRef(ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse orb ref: %w", err)
+	}
+
+	if !strings.EqualFold(ancestorRef.name, orbRef.name) {
+		return false, nil
+	}
+
+	ancestorVersion, ok := parseNumericVersion(ancestorRef.version)
+	if !ok || len(ancestorVersion) != 3 {
+		return false, nil
+	}
+	version, ok := parseNumericVersion(orbRef.version)
+	if !ok || len(version) != 3 {
+		return false, nil
+	}
+	return slices.Compare(ancestorVersion, version) <= 0, nil
+}
+
+// versions returns the published versions of the orb, newest first. It also
+// reports whether the list was truncated at orbVersionsCount, in which case
+// older versions are missing.
+func (c *CircleCI) versions(ctx context.Context, name string) ([][]int, bool, error) {
+	body, err := json.Marshal(map[string]any{
+		"query": orbVersionsQuery,
+		"variables": map[string]any{
+			"name":  name,
+			"count": orbVersionsCount,
+		},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	u := c.baseURL + "/graphql-unstable"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Circle-Token", c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, false, fmt.Errorf("unexpected response from %s (%d): %s",
+			u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data struct {
+			Orb *struct {
+				Versions []struct {
+					Version string `json:"version"`
+				} `json:"versions"`
+			} `json:"orb"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return nil, false, fmt.Errorf("failed to get versions of %s: %s", name, result.Errors[0].Message)
+	}
+	if result.Data.Orb == nil {
+		return nil, false, fmt.Errorf("orb %s not found", name)
+	}
+
+	versions := make([][]int, 0, len(result.Data.Orb.Versions))
+	for _, v := range result.Data.Orb.Versions {
+		if version, ok := parseNumericVersion(v.Version); ok && len(version) == 3 {
+			versions = append(versions, version)
+		}
+	}
+
+	// Do not rely on the order from the API.
+	slices.SortFunc(versions, func(a, b []int) int {
+		return slices.Compare(b, a)
+	})
+	return versions, len(result.Data.Orb.Versions) >= orbVersionsCount, nil
+}
+
+// parseNumericVersion parses a version with one to three numeric components
+// (e.g. "5", "5.1", or "5.1.0"), like orb versions and GitLab release tags.
+func parseNumericVersion(s string) ([]int, bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, false
+	}
+
+	version := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strings.HasPrefix(part, "+") {
+			return nil, false
+		}
+		version = append(version, n)
+	}
+	return version, true
+}
+
+func formatOrbVersion(version []int) string {
+	parts := make([]string, 0, len(version))
+	for _, n := range version {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, ".")
+}
+
+// IsExactOrbVersion returns true if the orb reference (e.g.
+// "circleci/node@5.1.0") is to an exact published version.
+func IsExactOrbVersion(s string) bool {
+	orbRef, err := ParseOrbRef(s)
+	if err != nil {
+		return false
+	}
+	version, ok := parseNumericVersion(orbRef.version)
+	return ok && len(version) == 3
+}
+
+// ParseOrbRef parses an orb reference of the form "namespace/orb@version".
+func ParseOrbRef(s string) (*OrbRef, error) {
+	name, version, ok := strings.Cut(s, "@")
+	if !ok {
+		return nil, fmt.Errorf("missing @ in orb reference: %q", s)
+	}
+	if version == "" {
+		return nil, fmt.Errorf("missing version in orb reference: %q", s)
+	}
+
+	namespace, orb, ok := strings.Cut(name, "/")
+	if !ok || namespace == "" || orb == "" || strings.Contains(orb, "/") {
+		return nil, fmt.Errorf("orb reference must be namespace/orb: %q", s)
+	}
+
+	return &OrbRef{
+		name:    name,
+		version: version,
+	}, nil
+}
+
+type OrbRef struct {
+	name    string
+	version string
+}
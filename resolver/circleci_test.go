@@ -0,0 +1,316 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testCircleCIServer starts a stand-in for the CircleCI GraphQL API. If token
+// is not empty, requests must be authenticated with it.
+func testCircleCIServer(tb testing.TB, token string) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql-unstable", func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Circle-Token") != token {
+			http.Error(w, "bad token", http.StatusUnauthorized)
+			return
+		}
+
+		var req struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Name string `json:"name"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !strings.Contains(req.Query, "orb(name: $name)") {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+
+		switch req.Variables.Name {
+		case "circleci/node":
+			fmt.Fprint(w, `{"data":{"orb":{"versions":[`+
+				`{"version":"6.0.1"},{"version":"6.0.0"},{"version":"5.10.0"},`+
+				`{"version":"5.2.0"},{"version":"5.1.1"},{"version":"5.1.0"},`+
+				`{"version":"5.0.0"}]}}}`)
+		case "circleci/many":
+			versions := make([]string, 0, orbVersionsCount)
+			for i := range orbVersionsCount {
+				versions = append(versions, fmt.Sprintf(`{"version":"9.0.%d"}`, orbVersionsCount-i))
+			}
+			fmt.Fprintf(w, `{"data":{"orb":{"versions":[%s]}}}`, strings.Join(versions, ","))
+		case "circleci/broken":
+			fmt.Fprint(w, `{"data":{"orb":null},"errors":[{"message":"something went wrong"}]}`)
+		default:
+			fmt.Fprint(w, `{"data":{"orb":null}}`)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCircleCI_Resolve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testCircleCIServer(t, "secret")
+
+	resolver, err := NewCircleCIWithURL(ctx, srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+		err  string
+	}{
+		{
+			name: "minor",
+			in:   "circleci/node@5.1",
+			exp:  "circleci/node@5.1.1",
+		},
+		{
+			name: "major",
+			in:   "circleci/node@5",
+			exp:  "circleci/node@5.10.0",
+		},
+		{
+			name: "exact",
+			in:   "circleci/node@5.1.0",
+			exp:  "circleci/node@5.1.0",
+		},
+		{
+			name: "volatile",
+			in:   "circleci/node@volatile",
+			exp:  "circleci/node@6.0.1",
+		},
+		{
+			name: "no_match",
+			in:   "circleci/node@4",
+			err:  "no published version",
+		},
+		{
+			name: "no_match_truncated",
+			in:   "circleci/many@8",
+			err:  "in the 200 most recent published versions",
+		},
+		{
+			name: "invalid_version",
+			in:   "circleci/node@dev:alpha",
+			err:  "invalid orb version",
+		},
+		{
+			name: "missing",
+			in:   "circleci/nope@1",
+			err:  "not found",
+		},
+		{
+			name: "graphql_error",
+			in:   "circleci/broken@1",
+			err:  "something went wrong",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.Resolve(ctx, tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+					t.Errorf("expected %q to contain %q", got, want)
+				}
+				return
+			} else if tc.err != "" {
+				t.Fatalf("expected error, got %q", result)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCircleCI_LatestVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testCircleCIServer(t, "")
+
+	resolver, err := NewCircleCIWithURL(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "major",
+			in:   "circleci/node@5",
+			exp:  "circleci/node@6",
+		},
+		{
+			name: "minor",
+			in:   "circleci/node@5.1",
+			exp:  "circleci/node@6.0",
+		},
+		{
+			name: "exact",
+			in:   "circleci/node@5.1.0",
+			exp:  "circleci/node@6.0.1",
+		},
+		{
+			name: "volatile",
+			in:   "circleci/node@volatile",
+			exp:  "circleci/node@volatile",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.LatestVersion(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCircleCI_IsAncestor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	resolver, err := NewCircleCIWithURL(ctx, "https://circleci.example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		ancestor string
+		ref      string
+		exp      bool
+	}{
+		{
+			name:     "older",
+			ancestor: "circleci/node@5.1.0",
+			ref:      "circleci/node@5.10.0",
+			exp:      true,
+		},
+		{
+			name:     "newer",
+			ancestor: "circleci/node@5.10.0",
+			ref:      "circleci/node@5.1.0",
+			exp:      false,
+		},
+		{
+			name:     "different_orb",
+			ancestor: "circleci/go@1.0.0",
+			ref:      "circleci/node@5.1.0",
+			exp:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.IsAncestor(ctx, tc.ancestor, tc.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+func TestParseOrbRef(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  *OrbRef
+		err  string
+	}{
+		{
+			name: "no_version",
+			in:   "circleci/node",
+			err:  "missing @",
+		},
+		{
+			name: "empty_version",
+			in:   "circleci/node@",
+			err:  "missing version",
+		},
+		{
+			name: "no_namespace",
+			in:   "node@5",
+			err:  "must be namespace/orb",
+		},
+		{
+			name: "ref",
+			in:   "circleci/node@5.1",
+			exp: &OrbRef{
+				name:    "circleci/node",
+				version: "5.1",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := ParseOrbRef(tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if str := err.Error(); !strings.Contains(str, tc.err) {
+					t.Errorf("expected %q to contain %q", str, tc.err)
+				}
+			} else if tc.err != "" {
+				t.Fatalf("expected error, but got %#v", ref)
+			}
+
+			if got, want := ref, tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %#v to be %#v", got, want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -50,3 +52,121 @@ func (g *Container) Resolve(ctx context.Context, value string) (string, error) {
 
 	return fmt.Sprintf("%s@%s", ref.Context().Name(), resp.Digest.String()), nil
 }
+
+// LatestVersion returns the reference with its tag upgraded to the newest tag
+// in the repository that has the same shape as the original tag. References
+// without an explicit tag, references with a digest, and tags that are not
+// version-like (e.g. "latest") are returned unchanged.
+func (g *Container) LatestVersion(ctx context.Context, value string) (string, error) {
+	ref, err := name.ParseReference(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Container ref: %w", err)
+	}
+
+	tag, ok := ref.(name.Tag)
+	if !ok || !strings.HasSuffix(value, ":"+tag.TagStr()) {
+		return value, nil
+	}
+
+	current := tag.TagStr()
+	if _, ok := parseContainerTag(current); !ok {
+		return value, nil
+	}
+
+	tags, err := remote.List(tag.Context(),
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return "", fmt.Errorf("failed to list container tags: %w", err)
+	}
+
+	latest := latestContainerTag(current, tags)
+	return strings.TrimSuffix(value, current) + latest, nil
+}
+
+// containerTagRe matches version-like container tags. It captures an optional
+// "v" prefix, one to three dot-separated numbers, and a suffix that does not
+// continue the version (e.g. "-alpine").
+var containerTagRe = regexp.MustCompile(`^(v?)(\d+(?:\.\d+){0,2})([^.\d].*)?$`)
+
+// containerSnapshotDigits is the minimum number of digits in the first number
+// of a snapshot tag, like a date (e.g. "20240329") or a build number. These are
+// not versions, so they are only compared to other snapshot tags.
+const containerSnapshotDigits = 6
+
+// containerTag is a version-like container tag.
+type containerTag struct {
+	prefix   string
+	numbers  []int
+	suffix   string
+	snapshot bool
+}
+
+// parseContainerTag parses the tag into its components, returning false if the
+// tag is not version-like.
+func parseContainerTag(s string) (*containerTag, bool) {
+	matches := containerTagRe.FindStringSubmatch(s)
+	if matches == nil {
+		return nil, false
+	}
+
+	parts := strings.Split(matches[2], ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+
+	return &containerTag{
+		prefix:   matches[1],
+		numbers:  numbers,
+		suffix:   matches[3],
+		snapshot: len(parts[0]) >= containerSnapshotDigits,
+	}, true
+}
+
+// sameShape returns true if both tags have the same prefix, precision, and
+// suffix, and are either both or neither snapshot tags, meaning one is an
+// upgrade candidate for the other.
+func (t *containerTag) sameShape(o *containerTag) bool {
+	return t.prefix == o.prefix && len(t.numbers) == len(o.numbers) && t.suffix == o.suffix &&
+		t.snapshot == o.snapshot
+}
+
+// less returns true if t is an older version than o.
+func (t *containerTag) less(o *containerTag) bool {
+	for i := range t.numbers {
+		if t.numbers[i] != o.numbers[i] {
+			return t.numbers[i] < o.numbers[i]
+		}
+	}
+	return false
+}
+
+// latestContainerTag returns the newest tag from tags that has the same
+// prefix, precision, and suffix as current. For example, "18-alpine" is
+// upgraded to "22-alpine", but never to "22" or "22.1-alpine". If no newer tag
+// exists, current is returned.
+func latestContainerTag(current string, tags []string) string {
+	best, ok := parseContainerTag(current)
+	if !ok {
+		return current
+	}
+
+	result := current
+	for _, candidate := range tags {
+		parsed, ok := parseContainerTag(candidate)
+		if !ok || !parsed.sameShape(best) {
+			continue
+		}
+
+		if best.less(parsed) {
+			best = parsed
+			result = candidate
+		}
+	}
+	return result
+}
@@ -2,8 +2,17 @@ package resolver
 
 import (
 	"context"
+	"io"
+	"log"
+	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 )
 
 func TestContainer_Resolve(t *testing.T) {
@@ -52,3 +61,181 @@ func TestContainer_Resolve(t *testing.T) {
 		})
 	}
 }
+
+func TestContainer_LatestVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tag := range []string{
+		"15", "15.3", "16", "16.4", "16.4-alpine", "latest",
+	} {
+		ref, err := name.ParseReference(host + "/postgres:" + tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img, remote.WithContext(ctx)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resolver, err := NewContainer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "major",
+			in:   host + "/postgres:15",
+			exp:  host + "/postgres:16",
+		},
+		{
+			name: "minor",
+			in:   host + "/postgres:15.3",
+			exp:  host + "/postgres:16.4",
+		},
+		{
+			name: "not_version",
+			in:   host + "/postgres:latest",
+			exp:  host + "/postgres:latest",
+		},
+		{
+			name: "implicit_tag",
+			in:   host + "/postgres",
+			exp:  host + "/postgres",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.LatestVersion(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLatestContainerTag(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{
+		"latest", "alpine",
+		"18", "18-alpine", "18.1", "18.1.0", "18.20.4", "18.20.4-alpine",
+		"20", "20-alpine", "20-bookworm",
+		"22", "22-alpine", "22.1", "22.9", "22.10", "22.10.0",
+		"v1", "v2", "v2.1",
+		"20240329", "20240606", "20240807",
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "major",
+			in:   "18",
+			exp:  "22",
+		},
+		{
+			name: "major_suffix",
+			in:   "18-alpine",
+			exp:  "22-alpine",
+		},
+		{
+			name: "minor_numeric_order",
+			in:   "18.1",
+			exp:  "22.10",
+		},
+		{
+			name: "patch",
+			in:   "18.1.0",
+			exp:  "22.10.0",
+		},
+		{
+			name: "patch_suffix",
+			in:   "18.20.4-alpine",
+			exp:  "18.20.4-alpine",
+		},
+		{
+			name: "major_skips_snapshot",
+			in:   "20",
+			exp:  "22",
+		},
+		{
+			name: "snapshot",
+			in:   "20240329",
+			exp:  "20240807",
+		},
+		{
+			name: "prefix",
+			in:   "v1",
+			exp:  "v2",
+		},
+		{
+			name: "already_latest",
+			in:   "22",
+			exp:  "22",
+		},
+		{
+			name: "not_version",
+			in:   "latest",
+			exp:  "latest",
+		},
+		{
+			name: "newer_than_all",
+			in:   "99",
+			exp:  "99",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := latestContainerTag(tc.in, tags), tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDefaultResolver_IsAncestor_Container(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Digests have no history, so a different digest is never an ancestor, even
+	// for the same image.
+	ok, err := new(DefaultResolver).IsAncestor(ctx,
+		"container://ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
+		"container://ubuntu@sha256:6015f66923d7afbc53558d7ccffd325d43b4e249f41a6e93eef074c9505d2233")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("expected different container digests to not be ancestors")
+	}
+}
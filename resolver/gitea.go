@@ -0,0 +1,266 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	GiteaBaseURL = coalesce(os.Getenv("GITEA_BASE_URL"), os.Getenv("FORGEJO_BASE_URL"))
+	GiteaToken   = coalesce(os.Getenv("GITEA_TOKEN"), os.Getenv("FORGEJO_TOKEN"))
+)
+
+// errGiteaNotFound is returned when the Gitea API responds with a 404.
+var errGiteaNotFound = errors.New("not found")
+
+func NormalizeGiteaRef(in string) string {
+	return GiteaProtocol + in
+}
+
+// Gitea resolves Gitea and Forgejo Actions references. References are either
+// relative to the configured instance (e.g. "actions/checkout@v4") or full
+// URLs (e.g. "https://code.forgejo.org/actions/checkout@v4").
+type Gitea struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewGitea creates a new resolver for Gitea and Forgejo Actions, using the
+// instance and token from the environment.
+func NewGitea(ctx context.Context) (*Gitea, error) {
+	return NewGiteaWithURL(ctx, GiteaBaseURL, GiteaToken)
+}
+
+// NewGiteaWithURL creates a new resolver for Gitea and Forgejo Actions for the
+// given instance. The token is only sent to that instance.
+func NewGiteaWithURL(ctx context.Context, baseURL, token string) (*Gitea, error) {
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid gitea base url %q", baseURL)
+		}
+	}
+
+	return &Gitea{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}, nil
+}
+
+func (g *Gitea) Resolve(ctx context.Context, value string) (string, error) {
+	giteaRef, err := g.parseRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	sha, err := g.commitSHA(ctx, giteaRef, giteaRef.ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit sha: %w", err)
+	}
+
+	return giteaRef.name() + "@" + sha, nil
+}
+
+func (g *Gitea) LatestVersion(ctx context.Context, value string) (string, error) {
+	giteaRef, err := g.parseRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	// Do not upgrade branch refs.
+	if err := g.get(ctx, giteaRef, "/branches/"+url.PathEscape(giteaRef.ref), nil); err == nil {
+		return value, nil
+	} else if !errors.Is(err, errGiteaNotFound) {
+		return "", fmt.Errorf("failed to fetch ref %s: %w", giteaRef.ref, err)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := g.get(ctx, giteaRef, "/releases/latest", &release); err != nil {
+		return "", fmt.Errorf("failed to get latest release: %w", err)
+	}
+
+	version := matchVersionPrecision(giteaRef.ref, release.TagName)
+	return giteaRef.name() + "@" + version, nil
+}
+
+// IsAncestor reports whether the commit pinned in ancestor is an ancestor of,
+// or the same as, the commit pinned in ref. Both references must be in the
+// same repository.
+func (g *Gitea) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	ancestorRef, err := g.parseRef(ancestor)
+	if err != nil {
+		return false, err
+	}
+
+	giteaRef, err := g.parseRef(ref)
+	if err != nil {
+		return false, err
+	}
+
+	if ancestorRef.baseURL != giteaRef.baseURL ||
+		!strings.EqualFold(ancestorRef.owner, giteaRef.owner) ||
+		!strings.EqualFold(ancestorRef.repo, giteaRef.repo) {
+		return false, nil
+	}
+
+	// The comparison lists the commits in the ancestor that are not in the ref,
+	// which is empty if the ancestor is reachable from the ref.
+	var comparison struct {
+		TotalCommits int `json:"total_commits"`
+	}
+	pth := "/compare/" + url.PathEscape(giteaRef.ref) + "..." + url.PathEscape(ancestorRef.ref)
+	if err := g.get(ctx, giteaRef, pth, &comparison); err != nil {
+		if errors.Is(err, errGiteaNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare %s to %s: %w", ancestorRef.ref, giteaRef.ref, err)
+	}
+	return comparison.TotalCommits == 0, nil
+}
+
+// commitSHA returns the commit SHA for the branch, tag, or commit.
+func (g *Gitea) commitSHA(ctx context.Context, giteaRef *GiteaRef, ref string) (string, error) {
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+
+	q := url.Values{}
+	q.Set("sha", ref)
+	q.Set("limit", "1")
+	q.Set("stat", "false")
+	q.Set("verification", "false")
+	q.Set("files", "false")
+	if err := g.get(ctx, giteaRef, "/commits?"+q.Encode(), &commits); err != nil {
+		return "", err
+	}
+
+	if len(commits) == 0 || commits[0].SHA == "" {
+		return "", fmt.Errorf("no commits found for %q", ref)
+	}
+	return commits[0].SHA, nil
+}
+
+// get calls the repository API at the given path, decoding the response into
+// out if it is not nil.
+func (g *Gitea) get(ctx context.Context, giteaRef *GiteaRef, pth string, out any) error {
+	u := giteaRef.baseURL + "/api/v1/repos/" +
+		url.PathEscape(giteaRef.owner) + "/" + url.PathEscape(giteaRef.repo) + pth
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Only send the token to the configured instance.
+	if g.token != "" && giteaRef.baseURL == g.baseURL {
+		req.Header.Set("Authorization", "token "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", u, errGiteaNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response from %s (%d): %s",
+			u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// parseRef parses the reference, using the configured instance for references
+// that are not full URLs.
+func (g *Gitea) parseRef(value string) (*GiteaRef, error) {
+	giteaRef, err := ParseGiteaRef(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gitea ref: %w", err)
+	}
+
+	if giteaRef.baseURL == "" {
+		if g.baseURL == "" {
+			return nil, fmt.Errorf("cannot resolve %q: GITEA_BASE_URL is not set", value)
+		}
+		giteaRef.baseURL = g.baseURL
+	}
+	return giteaRef, nil
+}
+
+// ParseGiteaRef parses a Gitea or Forgejo Actions reference, which is either
+// "owner/repo[/path]@ref" or "https://host/owner/repo[/path]@ref".
+func ParseGiteaRef(s string) (*GiteaRef, error) {
+	var baseURL string
+	rest := s
+	for _, scheme := range []string{"https://", "http://"} {
+		if after, ok := strings.CutPrefix(s, scheme); ok {
+			host, pth, ok := strings.Cut(after, "/")
+			if !ok || host == "" {
+				return nil, fmt.Errorf("missing host in gitea reference: %q", s)
+			}
+			baseURL, rest = scheme+host, pth
+			break
+		}
+	}
+
+	githubRef, err := ParseActionRef(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GiteaRef{
+		baseURL: baseURL,
+		url:     baseURL != "",
+		owner:   githubRef.owner,
+		repo:    githubRef.repo,
+		path:    githubRef.path,
+		ref:     githubRef.ref,
+	}, nil
+}
+
+type GiteaRef struct {
+	baseURL string
+	url     bool
+	owner   string
+	repo    string
+	path    string
+	ref     string
+}
+
+// name returns the reference without the version, in the same form as it was
+// parsed.
+func (r *GiteaRef) name() string {
+	name := r.owner + "/" + r.repo
+	if r.path != "" {
+		name = name + "/" + r.path
+	}
+	if r.url {
+		name = r.baseURL + "/" + name
+	}
+	return name
+}
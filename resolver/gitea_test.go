@@ -0,0 +1,313 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testGiteaSHA    = "11bd71901bbe5b1630ceea73d27597364c9af683"
+	testGiteaOldSHA = "2541b1294d2704b0964813337f33b291d3f8596b"
+)
+
+// testGiteaServer starts a stand-in for the Gitea API. If token is not empty,
+// requests must be authenticated with it.
+func testGiteaServer(tb testing.TB, token string) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/actions/checkout/commits", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("sha") {
+		case "v4", "main", testGiteaSHA:
+			fmt.Fprintf(w, `[{"sha":%q}]`, testGiteaSHA)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("GET /api/v1/repos/actions/checkout/branches/main", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"main"}`)
+	})
+	mux.HandleFunc("GET /api/v1/repos/actions/checkout/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name":"v5.1.0"}`)
+	})
+	mux.HandleFunc("GET /api/v1/repos/actions/checkout/compare/{basehead}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("basehead") {
+		case testGiteaSHA + "..." + testGiteaOldSHA:
+			fmt.Fprint(w, `{"total_commits":0}`)
+		case testGiteaOldSHA + "..." + testGiteaSHA:
+			fmt.Fprint(w, `{"total_commits":3}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" && got != "token "+token {
+			http.Error(w, "bad token", http.StatusUnauthorized)
+			return
+		}
+		if token != "" && r.Header.Get("Authorization") == "" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitea_Resolve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGiteaServer(t, "secret")
+	otherSrv := testGiteaServer(t, "")
+
+	resolver, err := NewGiteaWithURL(ctx, srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+		err  string
+	}{
+		{
+			name: "default",
+			in:   "actions/checkout@v4",
+			exp:  "actions/checkout@" + testGiteaSHA,
+		},
+		{
+			name: "path",
+			in:   "actions/checkout/path@main",
+			exp:  "actions/checkout/path@" + testGiteaSHA,
+		},
+		{
+			name: "url",
+			in:   otherSrv.URL + "/actions/checkout@v4",
+			exp:  otherSrv.URL + "/actions/checkout@" + testGiteaSHA,
+		},
+		{
+			name: "missing",
+			in:   "actions/checkout@v0",
+			err:  "not found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.Resolve(ctx, tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+					t.Errorf("expected %q to contain %q", got, want)
+				}
+				return
+			} else if tc.err != "" {
+				t.Fatalf("expected error, got %q", result)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitea_Resolve_noBaseURL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	resolver, err := NewGiteaWithURL(ctx, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := resolver.Resolve(ctx, "actions/checkout@v4"); err == nil {
+		t.Fatal("expected error")
+	} else if got, want := err.Error(), "GITEA_BASE_URL is not set"; !strings.Contains(got, want) {
+		t.Errorf("expected %q to contain %q", got, want)
+	}
+}
+
+func TestGitea_LatestVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGiteaServer(t, "")
+
+	resolver, err := NewGiteaWithURL(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "major",
+			in:   "actions/checkout@v4",
+			exp:  "actions/checkout@v5",
+		},
+		{
+			name: "patch",
+			in:   "actions/checkout@v4.0.0",
+			exp:  "actions/checkout@v5.1.0",
+		},
+		{
+			name: "skips_branch",
+			in:   "actions/checkout@main",
+			exp:  "actions/checkout@main",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.LatestVersion(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitea_IsAncestor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGiteaServer(t, "")
+
+	resolver, err := NewGiteaWithURL(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		ancestor string
+		ref      string
+		exp      bool
+	}{
+		{
+			name:     "ancestor",
+			ancestor: "actions/checkout@" + testGiteaOldSHA,
+			ref:      "actions/checkout@" + testGiteaSHA,
+			exp:      true,
+		},
+		{
+			name:     "descendant",
+			ancestor: "actions/checkout@" + testGiteaSHA,
+			ref:      "actions/checkout@" + testGiteaOldSHA,
+			exp:      false,
+		},
+		{
+			name:     "different_repo",
+			ancestor: "actions/setup-go@" + testGiteaOldSHA,
+			ref:      "actions/checkout@" + testGiteaSHA,
+			exp:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.IsAncestor(ctx, tc.ancestor, tc.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+func TestParseGiteaRef(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  *GiteaRef
+		err  string
+	}{
+		{
+			name: "no_ref",
+			in:   "foo/bar",
+			err:  "missing @",
+		},
+		{
+			name: "no_host",
+			in:   "https:///foo/bar@v1",
+			err:  "missing host",
+		},
+		{
+			name: "ref",
+			in:   "foo/bar/baz@v0",
+			exp: &GiteaRef{
+				owner: "foo",
+				repo:  "bar",
+				path:  "baz",
+				ref:   "v0",
+			},
+		},
+		{
+			name: "url",
+			in:   "https://code.forgejo.org/foo/bar@v0",
+			exp: &GiteaRef{
+				baseURL: "https://code.forgejo.org",
+				url:     true,
+				owner:   "foo",
+				repo:    "bar",
+				ref:     "v0",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := ParseGiteaRef(tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if str := err.Error(); !strings.Contains(str, tc.err) {
+					t.Errorf("expected %q to contain %q", str, tc.err)
+				}
+			} else if tc.err != "" {
+				t.Fatalf("expected error, but got %#v", ref)
+			}
+
+			if got, want := ref, tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %#v to be %#v", got, want)
+			}
+		})
+	}
+}
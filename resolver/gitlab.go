@@ -0,0 +1,338 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	GitLabBaseURL = coalesce(os.Getenv("GITLAB_BASE_URL"), os.Getenv("CI_SERVER_URL"), "https://gitlab.com")
+	GitLabToken   = os.Getenv("GITLAB_TOKEN")
+)
+
+// errGitLabNotFound is returned when the GitLab API responds with a 404.
+var errGitLabNotFound = errors.New("not found")
+
+// gitlabPartialVersionRe matches partial versions of CI/CD components (e.g. "1"
+// or "v1.2"), which refer to the latest matching release.
+var gitlabPartialVersionRe = regexp.MustCompile(`^v?\d+(\.\d+)?$`)
+
+// gitlabTagsPerPage is the number of tags requested per page.
+const gitlabTagsPerPage = 100
+
+func NormalizeGitLabRef(in string) string {
+	return GitLabProtocol + in
+}
+
+// NormalizeGitLabComponentRef normalizes a reference to the project of a CI/CD
+// component, whose version may be partial.
+func NormalizeGitLabComponentRef(in string) string {
+	return GitLabComponentProtocol + in
+}
+
+// GitLab resolves references to GitLab projects. References are either
+// relative to the configured instance (e.g. "my-group/my-project@v1.2") or
+// full URLs (e.g. "https://gitlab.com/my-group/my-project@v1.2"). Projects may
+// be in nested groups.
+type GitLab struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewGitLab creates a new resolver for GitLab, using the instance and token
+// from the environment.
+func NewGitLab(ctx context.Context) (*GitLab, error) {
+	return NewGitLabWithURL(ctx, GitLabBaseURL, GitLabToken)
+}
+
+// NewGitLabWithURL creates a new resolver for GitLab for the given instance.
+// The token is only sent to that instance.
+func NewGitLabWithURL(ctx context.Context, baseURL, token string) (*GitLab, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid gitlab base url %q", baseURL)
+	}
+
+	return &GitLab{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}, nil
+}
+
+// Resolve resolves the exact git ref (e.g. a project include ref) to a commit
+// SHA.
+func (g *GitLab) Resolve(ctx context.Context, value string) (string, error) {
+	gitlabRef, err := g.parseRef(value)
+	if err != nil {
+		return "", err
+	}
+	return g.resolve(ctx, gitlabRef, gitlabRef.ref)
+}
+
+// ResolveComponent resolves the version of a CI/CD component to a commit SHA.
+// Partial versions (e.g. "1.0") are resolved to the highest matching tag (e.g.
+// "1.0.3"), falling back to the version itself if no tag matches.
+func (g *GitLab) ResolveComponent(ctx context.Context, value string) (string, error) {
+	gitlabRef, err := g.parseRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	ref := gitlabRef.ref
+	if gitlabPartialVersionRe.MatchString(ref) {
+		tag, err := g.latestMatchingTag(ctx, gitlabRef)
+		if err != nil {
+			return "", err
+		}
+		if tag != "" {
+			ref = tag
+		}
+	}
+	return g.resolve(ctx, gitlabRef, ref)
+}
+
+// resolve returns the reference pinned to the commit SHA of the given ref in
+// the project.
+func (g *GitLab) resolve(ctx context.Context, gitlabRef *GitLabRef, ref string) (string, error) {
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if err := g.get(ctx, gitlabRef, "/repository/commits/"+url.PathEscape(ref), &commit); err != nil {
+		return "", fmt.Errorf("failed to get commit sha: %w", err)
+	}
+	if commit.ID == "" {
+		return "", fmt.Errorf("failed to get commit sha: no commit found for %q", ref)
+	}
+
+	return gitlabRef.name() + "@" + commit.ID, nil
+}
+
+func (g *GitLab) LatestVersion(ctx context.Context, value string) (string, error) {
+	gitlabRef, err := g.parseRef(value)
+	if err != nil {
+		return "", err
+	}
+
+	// Do not upgrade branch refs.
+	if err := g.get(ctx, gitlabRef, "/repository/branches/"+url.PathEscape(gitlabRef.ref), nil); err == nil {
+		return value, nil
+	} else if !errors.Is(err, errGitLabNotFound) {
+		return "", fmt.Errorf("failed to fetch ref %s: %w", gitlabRef.ref, err)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := g.get(ctx, gitlabRef, "/releases/permalink/latest", &release); err != nil {
+		return "", fmt.Errorf("failed to get latest release: %w", err)
+	}
+
+	version := matchVersionPrecision(gitlabRef.ref, release.TagName)
+	return gitlabRef.name() + "@" + version, nil
+}
+
+// latestMatchingTag returns the highest tag that starts with the partial version
+// in the ref (e.g. "1.0.3" for "1.0"), or the empty string if no tag matches.
+// Tags that are not plain versions (e.g. "1.0.0-rc.1") are ignored.
+func (g *GitLab) latestMatchingTag(ctx context.Context, gitlabRef *GitLabRef) (string, error) {
+	prefix := gitlabRef.ref + "."
+	vPrefix := strings.HasPrefix(prefix, "v")
+
+	var latest string
+	var latestVersion []int
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Set("search", "^"+prefix)
+		q.Set("per_page", strconv.Itoa(gitlabTagsPerPage))
+		q.Set("page", strconv.Itoa(page))
+
+		var tags []struct {
+			Name string `json:"name"`
+		}
+		if err := g.get(ctx, gitlabRef, "/repository/tags?"+q.Encode(), &tags); err != nil {
+			return "", fmt.Errorf("failed to list tags: %w", err)
+		}
+
+		for _, tag := range tags {
+			if !strings.HasPrefix(tag.Name, prefix) {
+				continue
+			}
+
+			name := tag.Name
+			if vPrefix {
+				name = strings.TrimPrefix(name, "v")
+			}
+			version, ok := parseNumericVersion(name)
+			if !ok {
+				continue
+			}
+
+			if latest == "" || slices.Compare(version, latestVersion) > 0 {
+				latest, latestVersion = tag.Name, version
+			}
+		}
+
+		if len(tags) < gitlabTagsPerPage {
+			return latest, nil
+		}
+	}
+}
+
+// IsAncestor reports whether the commit pinned in ancestor is an ancestor of,
+// or the same as, the commit pinned in ref. Both references must be in the
+// same project.
+func (g *GitLab) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	ancestorRef, err := g.parseRef(ancestor)
+	if err != nil {
+		return false, err
+	}
+
+	gitlabRef, err := g.parseRef(ref)
+	if err != nil {
+		return false, err
+	}
+
+	if ancestorRef.baseURL != gitlabRef.baseURL ||
+		!strings.EqualFold(ancestorRef.project, gitlabRef.project) {
+		return false, nil
+	}
+
+	// The comparison lists the commits in the ancestor that are not in the ref,
+	// which is empty if the ancestor is reachable from the ref.
+	var comparison struct {
+		Commits []struct{} `json:"commits"`
+	}
+	q := url.Values{}
+	q.Set("from", gitlabRef.ref)
+	q.Set("to", ancestorRef.ref)
+	if err := g.get(ctx, gitlabRef, "/repository/compare?"+q.Encode(), &comparison); err != nil {
+		if errors.Is(err, errGitLabNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare %s to %s: %w", ancestorRef.ref, gitlabRef.ref, err)
+	}
+	return len(comparison.Commits) == 0, nil
+}
+
+// get calls the project API at the given path, decoding the response into out
+// if it is not nil.
+func (g *GitLab) get(ctx context.Context, gitlabRef *GitLabRef, pth string, out any) error {
+	u := gitlabRef.baseURL + "/api/v4/projects/" + url.PathEscape(gitlabRef.project) + pth
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Only send the token to the configured instance.
+	if g.token != "" && gitlabRef.baseURL == g.baseURL {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", u, errGitLabNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response from %s (%d): %s",
+			u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// parseRef parses the reference, using the configured instance for references
+// that are not full URLs.
+func (g *GitLab) parseRef(value string) (*GitLabRef, error) {
+	gitlabRef, err := ParseGitLabRef(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gitlab ref: %w", err)
+	}
+
+	if gitlabRef.baseURL == "" {
+		gitlabRef.baseURL = g.baseURL
+	}
+	return gitlabRef, nil
+}
+
+// ParseGitLabRef parses a GitLab project reference, which is either
+// "group/project@ref" or "https://host/group/project@ref". Projects may be in
+// nested groups (e.g. "group/subgroup/project@ref").
+func ParseGitLabRef(s string) (*GitLabRef, error) {
+	var baseURL string
+	rest := s
+	for _, scheme := range []string{"https://", "http://"} {
+		if after, ok := strings.CutPrefix(s, scheme); ok {
+			host, pth, ok := strings.Cut(after, "/")
+			if !ok || host == "" {
+				return nil, fmt.Errorf("missing host in gitlab reference: %q", s)
+			}
+			baseURL, rest = scheme+host, pth
+			break
+		}
+	}
+
+	project, ref, ok := strings.Cut(rest, "@")
+	if !ok {
+		return nil, fmt.Errorf("missing @ in gitlab reference: %q", s)
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("missing ref in gitlab reference: %q", s)
+	}
+
+	project = strings.Trim(project, "/")
+	if !strings.Contains(project, "/") {
+		return nil, fmt.Errorf("missing group in gitlab reference: %q", s)
+	}
+
+	return &GitLabRef{
+		baseURL: baseURL,
+		url:     baseURL != "",
+		project: project,
+		ref:     ref,
+	}, nil
+}
+
+type GitLabRef struct {
+	baseURL string
+	url     bool
+	project string
+	ref     string
+}
+
+// name returns the reference without the version, in the same form as it was
+// parsed.
+func (r *GitLabRef) name() string {
+	if r.url {
+		return r.baseURL + "/" + r.project
+	}
+	return r.project
+}
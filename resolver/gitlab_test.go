@@ -0,0 +1,388 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testGitLabSHA    = "c4a0b883114b00d8d76b479c820ce7950211c99b"
+	testGitLabOldSHA = "a12a3943b4bdde767164f792f33f40b04645d846"
+)
+
+// testGitLabServer starts a stand-in for the GitLab API. If token is not empty,
+// requests must be authenticated with it.
+func testGitLabServer(tb testing.TB, token string) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/repository/commits/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("project") != "my-group/sub/templates" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.PathValue("ref") {
+		case "v1.2", "main", "1.1.0", testGitLabSHA:
+			fmt.Fprintf(w, `{"id":%q}`, testGitLabSHA)
+		case "1.0.10", "v1.2.5":
+			fmt.Fprintf(w, `{"id":%q}`, testGitLabOldSHA)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/repository/tags", func(w http.ResponseWriter, r *http.Request) {
+		prefix := strings.TrimPrefix(r.URL.Query().Get("search"), "^")
+
+		var names []string
+		for _, name := range []string{"1.0.0", "1.0.9", "1.0.10", "1.0.11-rc.1", "1.1.0", "v1.2", "v1.2.5"} {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, fmt.Sprintf(`{"name":%q}`, name))
+			}
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(names, ","))
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/repository/branches/{branch}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("branch") != "main" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"name":"main"}`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/releases/permalink/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name":"v2.3.1"}`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/repository/compare", func(w http.ResponseWriter, r *http.Request) {
+		from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+		switch {
+		case from == testGitLabSHA && to == testGitLabOldSHA:
+			fmt.Fprint(w, `{"commits":[]}`)
+		case from == testGitLabOldSHA && to == testGitLabSHA:
+			fmt.Fprint(w, `{"commits":[{},{}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "" && got != token {
+			http.Error(w, "bad token", http.StatusUnauthorized)
+			return
+		}
+		if token != "" && r.Header.Get("PRIVATE-TOKEN") == "" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitLab_Resolve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGitLabServer(t, "secret")
+	otherSrv := testGitLabServer(t, "")
+
+	resolver, err := NewGitLabWithURL(ctx, srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+		err  string
+	}{
+		{
+			// Project refs are exact, even though "v1.2.5" also exists.
+			name: "default",
+			in:   "my-group/sub/templates@v1.2",
+			exp:  "my-group/sub/templates@" + testGitLabSHA,
+		},
+		{
+			name: "url",
+			in:   otherSrv.URL + "/my-group/sub/templates@main",
+			exp:  otherSrv.URL + "/my-group/sub/templates@" + testGitLabSHA,
+		},
+		{
+			name: "missing",
+			in:   "my-group/sub/templates@v0",
+			err:  "not found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.Resolve(ctx, tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+					t.Errorf("expected %q to contain %q", got, want)
+				}
+				return
+			} else if tc.err != "" {
+				t.Fatalf("expected error, got %q", result)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitLab_ResolveComponent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGitLabServer(t, "")
+
+	resolver, err := NewGitLabWithURL(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+		err  string
+	}{
+		{
+			name: "partial_version",
+			in:   "my-group/sub/templates@1.0",
+			exp:  "my-group/sub/templates@" + testGitLabOldSHA,
+		},
+		{
+			name: "partial_major_version",
+			in:   "my-group/sub/templates@1",
+			exp:  "my-group/sub/templates@" + testGitLabSHA,
+		},
+		{
+			name: "partial_version_prefix",
+			in:   "my-group/sub/templates@v1.2",
+			exp:  "my-group/sub/templates@" + testGitLabOldSHA,
+		},
+		{
+			name: "branch",
+			in:   "my-group/sub/templates@main",
+			exp:  "my-group/sub/templates@" + testGitLabSHA,
+		},
+		{
+			name: "missing",
+			in:   "my-group/sub/templates@v0",
+			err:  "not found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.ResolveComponent(ctx, tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+					t.Errorf("expected %q to contain %q", got, want)
+				}
+				return
+			} else if tc.err != "" {
+				t.Fatalf("expected error, got %q", result)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitLab_LatestVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGitLabServer(t, "")
+
+	resolver, err := NewGitLabWithURL(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "minor",
+			in:   "my-group/sub/templates@v1.2",
+			exp:  "my-group/sub/templates@v2.3",
+		},
+		{
+			name: "skips_branch",
+			in:   "my-group/sub/templates@main",
+			exp:  "my-group/sub/templates@main",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.LatestVersion(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGitLab_IsAncestor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	srv := testGitLabServer(t, "")
+
+	resolver, err := NewGitLabWithURL(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		ancestor string
+		ref      string
+		exp      bool
+	}{
+		{
+			name:     "ancestor",
+			ancestor: "my-group/sub/templates@" + testGitLabOldSHA,
+			ref:      "my-group/sub/templates@" + testGitLabSHA,
+			exp:      true,
+		},
+		{
+			name:     "descendant",
+			ancestor: "my-group/sub/templates@" + testGitLabSHA,
+			ref:      "my-group/sub/templates@" + testGitLabOldSHA,
+			exp:      false,
+		},
+		{
+			name:     "different_project",
+			ancestor: "my-group/other@" + testGitLabOldSHA,
+			ref:      "my-group/sub/templates@" + testGitLabSHA,
+			exp:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := resolver.IsAncestor(ctx, tc.ancestor, tc.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %t to be %t", got, want)
+			}
+		})
+	}
+}
+
+func TestParseGitLabRef(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  *GitLabRef
+		err  string
+	}{
+		{
+			name: "no_ref",
+			in:   "foo/bar",
+			err:  "missing @",
+		},
+		{
+			name: "no_group",
+			in:   "bar@v1",
+			err:  "missing group",
+		},
+		{
+			name: "no_host",
+			in:   "https:///foo/bar@v1",
+			err:  "missing host",
+		},
+		{
+			name: "ref",
+			in:   "foo/bar@v0",
+			exp: &GitLabRef{
+				project: "foo/bar",
+				ref:     "v0",
+			},
+		},
+		{
+			name: "nested",
+			in:   "foo/bar/baz@v0",
+			exp: &GitLabRef{
+				project: "foo/bar/baz",
+				ref:     "v0",
+			},
+		},
+		{
+			name: "url",
+			in:   "https://gitlab.example.com/foo/bar@v0",
+			exp: &GitLabRef{
+				baseURL: "https://gitlab.example.com",
+				url:     true,
+				project: "foo/bar",
+				ref:     "v0",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := ParseGitLabRef(tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				}
+				if str := err.Error(); !strings.Contains(str, tc.err) {
+					t.Errorf("expected %q to contain %q", str, tc.err)
+				}
+			} else if tc.err != "" {
+				t.Fatalf("expected error, but got %#v", ref)
+			}
+
+			if got, want := ref, tc.exp; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %#v to be %#v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultLockfile is the default path to the lockfile.
+const DefaultLockfile = ".ratchet.lock"
+
+// lockfileVersion is the current version of the lockfile format.
+const lockfileVersion = 1
+
+// Lockfile is a record of resolved references. It implements [Resolver] so
+// references can be pinned from the lockfile without any network calls.
+type Lockfile struct {
+	lock sync.RWMutex
+	refs map[string]*LockEntry
+}
+
+// LockEntry is a single resolution in the lockfile.
+type LockEntry struct {
+	Resolved   string    `json:"resolved"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// lockfileJSON is the on-disk representation of the lockfile.
+type lockfileJSON struct {
+	Version int                   `json:"version"`
+	Refs    map[string]*LockEntry `json:"refs"`
+}
+
+// NewLockfile creates a new, empty lockfile.
+func NewLockfile() *Lockfile {
+	return &Lockfile{
+		refs: make(map[string]*LockEntry, 8),
+	}
+}
+
+// LoadLockfile reads the lockfile at the given path.
+func LoadLockfile(pth string) (*Lockfile, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var f lockfileJSON
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", pth, err)
+	}
+
+	if f.Version != lockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d", f.Version)
+	}
+
+	l := NewLockfile()
+	for ref, entry := range f.Refs {
+		if entry == nil || entry.Resolved == "" {
+			return nil, fmt.Errorf("lockfile entry for %q is missing a resolved value", ref)
+		}
+		l.refs[ref] = entry
+	}
+	return l, nil
+}
+
+// Save writes the lockfile to the given path. Entries are sorted by reference
+// so the output is stable between runs.
+func (l *Lockfile) Save(pth string) error {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&lockfileJSON{
+		Version: lockfileVersion,
+		Refs:    l.refs,
+	}); err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+
+	if err := os.WriteFile(pth, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// Set records the resolution for the given reference.
+func (l *Lockfile) Set(ref, resolved string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refs[ref] = &LockEntry{
+		Resolved:   resolved,
+		ResolvedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Resolve returns the resolution for the ref from the lockfile. It returns an
+// error if the ref is not in the lockfile.
+func (l *Lockfile) Resolve(ctx context.Context, ref string) (string, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	entry, ok := l.refs[ref]
+	if !ok {
+		return "", fmt.Errorf("%q is not in the lockfile", DenormalizeRef(ref))
+	}
+	return entry.Resolved, nil
+}
+
+// LatestVersion is not supported for lockfiles, since the lockfile only
+// records resolutions.
+func (l *Lockfile) LatestVersion(ctx context.Context, ref string) (string, error) {
+	return "", fmt.Errorf("cannot upgrade %q from a lockfile", DenormalizeRef(ref))
+}
+
+// Record returns a resolver that resolves references using res and records
+// each successful resolution in the lockfile.
+func (l *Lockfile) Record(res Resolver) Resolver {
+	return &lockfileRecorder{
+		resolver: res,
+		lockfile: l,
+	}
+}
+
+type lockfileRecorder struct {
+	resolver Resolver
+	lockfile *Lockfile
+}
+
+func (r *lockfileRecorder) Resolve(ctx context.Context, ref string) (string, error) {
+	resolved, err := r.resolver.Resolve(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	r.lockfile.Set(ref, resolved)
+	return resolved, nil
+}
+
+func (r *lockfileRecorder) LatestVersion(ctx context.Context, ref string) (string, error) {
+	return r.resolver.LatestVersion(ctx, ref)
+}
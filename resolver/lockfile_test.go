@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockfile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	test, err := NewTest(map[string]*TestResult{
+		"actions://good/repo@v0": {
+			Resolved: "good/repo@a12a3943",
+		},
+		"container://ubuntu:22.04": {
+			Resolved: "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockfile := NewLockfile()
+	recorder := lockfile.Record(test)
+	for _, ref := range []string{"actions://good/repo@v0", "container://ubuntu:22.04"} {
+		if _, err := recorder.Resolve(ctx, ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pth := filepath.Join(t.TempDir(), DefaultLockfile)
+	if err := lockfile.Save(pth); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLockfile(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+		err  string
+	}{
+		{
+			name: "actions",
+			in:   "actions://good/repo@v0",
+			exp:  "good/repo@a12a3943",
+		},
+		{
+			name: "container",
+			in:   "container://ubuntu:22.04",
+			exp:  "ubuntu@sha256:47f14534bda344d9fe6ffd6effb95eefe579f4be0d508b7445cf77f61a0e5724",
+		},
+		{
+			name: "missing",
+			in:   "actions://good/repo@v1",
+			err:  `"good/repo@v1" is not in the lockfile`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := loaded.Resolve(ctx, tc.in)
+			if err != nil {
+				if tc.err == "" {
+					t.Fatal(err)
+				} else {
+					if got, want := err.Error(), tc.err; !strings.Contains(got, want) {
+						t.Errorf("expected %q to contain %q", got, want)
+					}
+				}
+			} else if tc.err != "" {
+				t.Fatalf("expected error, but got %q", result)
+			}
+
+			if got, want := result, tc.exp; got != want {
+				t.Errorf("expected %q to be %q", got, want)
+			}
+		})
+	}
+}
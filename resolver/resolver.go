@@ -7,8 +7,12 @@ import (
 )
 
 const (
-	ActionsProtocol   = "actions://"
-	ContainerProtocol = "container://"
+	ActionsProtocol         = "actions://"
+	ContainerProtocol       = "container://"
+	GiteaProtocol           = "gitea://"
+	GitLabProtocol          = "gitlab://"
+	GitLabComponentProtocol = "gitlab-component://"
+	OrbProtocol             = "orb://"
 )
 
 // Resolver is an interface that resolvers can implement.
@@ -24,10 +28,33 @@ type Resolver interface {
 	LatestVersion(context.Context, string) (string, error)
 }
 
+// CommitVerifier is an interface that resolvers can implement to verify that
+// pinned commits belong to the referenced repository.
+type CommitVerifier interface {
+	// VerifyCommit reports whether the commit in the given pinned reference is
+	// reachable from the default branch of the referenced repository, or from a
+	// branch or tag that matches the given version (e.g. the version recorded in
+	// the ratchet comment), which may be empty. If the provided context is
+	// canceled, the verification is also canceled.
+	VerifyCommit(ctx context.Context, ref, version string) (bool, error)
+}
+
+// CommitComparer is an interface that resolvers can implement to compare two
+// pinned references to the same upstream.
+type CommitComparer interface {
+	// IsAncestor reports whether the pinned reference ancestor is an ancestor
+	// of the pinned reference ref. If the provided context is canceled, the
+	// comparison is also canceled.
+	IsAncestor(ctx context.Context, ancestor, ref string) (bool, error)
+}
+
 // DefaultResolver is the default resolver.
 type DefaultResolver struct {
 	actions   *Actions
 	container *Container
+	gitea     *Gitea
+	gitlab    *GitLab
+	circleci  *CircleCI
 }
 
 // NewDefaultResolver returns the default resolver.
@@ -42,9 +69,27 @@ func NewDefaultResolver(ctx context.Context) (Resolver, error) {
 		return nil, fmt.Errorf("failed to setup docker resolver: %w", err)
 	}
 
+	gitea, err := NewGitea(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup gitea resolver: %w", err)
+	}
+
+	gitlab, err := NewGitLab(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup gitlab resolver: %w", err)
+	}
+
+	circleci, err := NewCircleCI(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup circleci resolver: %w", err)
+	}
+
 	return &DefaultResolver{
 		actions:   actions,
 		container: container,
+		gitea:     gitea,
+		gitlab:    gitlab,
+		circleci:  circleci,
 	}, nil
 }
 
@@ -55,6 +100,14 @@ func (r *DefaultResolver) Resolve(ctx context.Context, ref string) (string, erro
 		return r.actions.Resolve(ctx, strings.TrimPrefix(ref, ActionsProtocol))
 	case strings.HasPrefix(ref, ContainerProtocol):
 		return r.container.Resolve(ctx, strings.TrimPrefix(ref, ContainerProtocol))
+	case strings.HasPrefix(ref, GiteaProtocol):
+		return r.gitea.Resolve(ctx, strings.TrimPrefix(ref, GiteaProtocol))
+	case strings.HasPrefix(ref, GitLabProtocol):
+		return r.gitlab.Resolve(ctx, strings.TrimPrefix(ref, GitLabProtocol))
+	case strings.HasPrefix(ref, GitLabComponentProtocol):
+		return r.gitlab.ResolveComponent(ctx, strings.TrimPrefix(ref, GitLabComponentProtocol))
+	case strings.HasPrefix(ref, OrbProtocol):
+		return r.circleci.Resolve(ctx, strings.TrimPrefix(ref, OrbProtocol))
 	default:
 		return "", fmt.Errorf("missing resolver protocol")
 	}
@@ -70,16 +123,111 @@ func (r *DefaultResolver) LatestVersion(ctx context.Context, ref string) (string
 		}
 		return NormalizeActionsRef(res), nil
 	case strings.HasPrefix(ref, ContainerProtocol):
-		// TODO: Figure out a strategy for container upgrades.
-		return ref, nil
+		res, err := r.container.LatestVersion(ctx, strings.TrimPrefix(ref, ContainerProtocol))
+		if err != nil {
+			return "", fmt.Errorf("failed to upgrade ref: %w", err)
+		}
+		return NormalizeContainerRef(res), nil
+	case strings.HasPrefix(ref, GiteaProtocol):
+		res, err := r.gitea.LatestVersion(ctx, strings.TrimPrefix(ref, GiteaProtocol))
+		if err != nil {
+			return "", fmt.Errorf("failed to upgrade ref: %w", err)
+		}
+		return NormalizeGiteaRef(res), nil
+	case strings.HasPrefix(ref, GitLabProtocol):
+		res, err := r.gitlab.LatestVersion(ctx, strings.TrimPrefix(ref, GitLabProtocol))
+		if err != nil {
+			return "", fmt.Errorf("failed to upgrade ref: %w", err)
+		}
+		return NormalizeGitLabRef(res), nil
+	case strings.HasPrefix(ref, GitLabComponentProtocol):
+		res, err := r.gitlab.LatestVersion(ctx, strings.TrimPrefix(ref, GitLabComponentProtocol))
+		if err != nil {
+			return "", fmt.Errorf("failed to upgrade ref: %w", err)
+		}
+		return NormalizeGitLabComponentRef(res), nil
+	case strings.HasPrefix(ref, OrbProtocol):
+		res, err := r.circleci.LatestVersion(ctx, strings.TrimPrefix(ref, OrbProtocol))
+		if err != nil {
+			return "", fmt.Errorf("failed to upgrade ref: %w", err)
+		}
+		return NormalizeOrbRef(res), nil
 	default:
 		return "", fmt.Errorf("missing resolver protocol")
 	}
 }
 
+// VerifyCommit verifies the pinned commit in the ref. Container digests are
+// content-addressed, so they are always considered verified.
+func (r *DefaultResolver) VerifyCommit(ctx context.Context, ref, version string) (bool, error) {
+	switch {
+	case strings.HasPrefix(ref, ActionsProtocol):
+		ok, err := r.actions.VerifyCommit(ctx, strings.TrimPrefix(ref, ActionsProtocol), version)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify ref: %w", err)
+		}
+		return ok, nil
+	case strings.HasPrefix(ref, ContainerProtocol):
+		return true, nil
+	default:
+		return false, fmt.Errorf("missing resolver protocol")
+	}
+}
+
+// IsAncestor compares the pinned refs. Container digests have no history, so a
+// different digest is never considered an ancestor, since it may belong to an
+// unrelated image.
+func (r *DefaultResolver) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	switch {
+	case strings.HasPrefix(ancestor, ActionsProtocol) && strings.HasPrefix(ref, ActionsProtocol):
+		ok, err := r.actions.IsAncestor(ctx,
+			strings.TrimPrefix(ancestor, ActionsProtocol), strings.TrimPrefix(ref, ActionsProtocol))
+		if err != nil {
+			return false, fmt.Errorf("failed to compare refs: %w", err)
+		}
+		return ok, nil
+	case strings.HasPrefix(ancestor, ContainerProtocol) && strings.HasPrefix(ref, ContainerProtocol):
+		return false, nil
+	case strings.HasPrefix(ancestor, GiteaProtocol) && strings.HasPrefix(ref, GiteaProtocol):
+		ok, err := r.gitea.IsAncestor(ctx,
+			strings.TrimPrefix(ancestor, GiteaProtocol), strings.TrimPrefix(ref, GiteaProtocol))
+		if err != nil {
+			return false, fmt.Errorf("failed to compare refs: %w", err)
+		}
+		return ok, nil
+	case strings.HasPrefix(ancestor, GitLabProtocol) && strings.HasPrefix(ref, GitLabProtocol):
+		ok, err := r.gitlab.IsAncestor(ctx,
+			strings.TrimPrefix(ancestor, GitLabProtocol), strings.TrimPrefix(ref, GitLabProtocol))
+		if err != nil {
+			return false, fmt.Errorf("failed to compare refs: %w", err)
+		}
+		return ok, nil
+	case strings.HasPrefix(ancestor, GitLabComponentProtocol) && strings.HasPrefix(ref, GitLabComponentProtocol):
+		ok, err := r.gitlab.IsAncestor(ctx,
+			strings.TrimPrefix(ancestor, GitLabComponentProtocol), strings.TrimPrefix(ref, GitLabComponentProtocol))
+		if err != nil {
+			return false, fmt.Errorf("failed to compare refs: %w", err)
+		}
+		return ok, nil
+	case strings.HasPrefix(ancestor, OrbProtocol) && strings.HasPrefix(ref, OrbProtocol):
+		ok, err := r.circleci.IsAncestor(ctx,
+			strings.TrimPrefix(ancestor, OrbProtocol), strings.TrimPrefix(ref, OrbProtocol))
+		if err != nil {
+			return false, fmt.Errorf("failed to compare refs: %w", err)
+		}
+		return ok, nil
+	default:
+		return false, fmt.Errorf("missing or mismatched resolver protocol")
+	}
+}
+
 // DenormalizeRef removes the reference prefix.
 func DenormalizeRef(in string) string {
 	in = strings.TrimPrefix(in, ActionsProtocol)
 	in = strings.TrimPrefix(in, ContainerProtocol)
+	in = strings.TrimPrefix(in, GiteaProtocol)
+	in = strings.TrimPrefix(in, GitLabProtocol)
+	in = strings.TrimPrefix(in, GitLabComponentProtocol)
+	in = strings.TrimPrefix(in, OrbProtocol)
 	return in
 }